@@ -0,0 +1,216 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// intacct flags appear as true/false, T/F, Yes/No, Y/N, 1/0 and
+// active/inactive depending upon the object and api version.  Bool,
+// NullBool and the ResultMap accessors accept all of these.  The BoolTF,
+// BoolYesNo and BoolActive types also accept all variants but marshal
+// in the format expected by the field.
+//
+// Unrecognized values decode as false.  Result.Decode for a Service with
+// StrictBool set then rechecks the payload with checkBools and returns an
+// error instead.
+
+// ParseBool parses intacct's boolean variants ignoring case and
+// surrounding spaces: true/false, t/f, yes/no, y/n, 1/0 and
+// active/inactive.
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "yes", "y", "1", "active":
+		return true, nil
+	case "false", "f", "no", "n", "0", "inactive":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// boolTypes are the types checked by checkBools
+var boolTypes = map[reflect.Type]bool{
+	reflect.TypeOf(Bool(false)):       true,
+	reflect.TypeOf(BoolTF(false)):     true,
+	reflect.TypeOf(BoolYesNo(false)):  true,
+	reflect.TypeOf(BoolActive(false)): true,
+}
+
+// boolPaths adds the element paths, relative to the element decoded
+// into t, of fields with a type in boolTypes.
+func boolPaths(t reflect.Type, prefix string, paths map[string]bool, visiting map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if boolTypes[t] {
+		paths[prefix] = true
+		return
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if (f.PkgPath != "" && !f.Anonymous) || f.Name == "XMLName" {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("xml"), ",")
+		if tag[0] == "-" || len(tag) > 1 && tag[1] != "" && tag[1] != "omitempty" {
+			continue
+		}
+		name := tag[0]
+		if name == "" && f.Anonymous {
+			boolPaths(f.Type, prefix, paths, visiting)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		path := prefix
+		for _, n := range strings.Split(name, ">") {
+			fld := strings.Fields(n)
+			path += "/" + fld[len(fld)-1]
+		}
+		boolPaths(f.Type, path, paths, visiting)
+	}
+}
+
+// checkBools returns an error for the first element of payload decoded
+// into a bool type field that ParseBool does not recognize.  Only the
+// first record is checked when all is false.
+func checkBools(payload []byte, t reflect.Type, all bool) error {
+	paths := make(map[string]bool)
+	boolPaths(t, "", paths, make(map[reflect.Type]bool))
+	if len(paths) == 0 {
+		return nil
+	}
+	var stack []string
+	var text string
+	record := -1
+	dx := xml.NewDecoder(bytes.NewReader(payload))
+	for {
+		tk, err := dx.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch tx := tk.(type) {
+		case xml.StartElement:
+			path := ""
+			if len(stack) == 0 {
+				if record++; record > 0 && !all {
+					return nil
+				}
+			} else {
+				path = stack[len(stack)-1] + "/" + tx.Name.Local
+			}
+			stack, text = append(stack, path), ""
+		case xml.CharData:
+			text += string(tx)
+		case xml.EndElement:
+			path := stack[len(stack)-1]
+			if stack = stack[:len(stack)-1]; paths[path] {
+				if _, err := parseBoolText(text, true); err != nil {
+					return fmt.Errorf("%d: %s: %v", record, strings.TrimPrefix(path, "/"), err)
+				}
+			}
+			text = ""
+		}
+	}
+}
+
+func parseBoolText(s string, strict bool) (bool, error) {
+	if strings.TrimSpace(s) == "" {
+		return false, nil
+	}
+	b, err := ParseBool(s)
+	if err != nil && !strict {
+		return false, nil
+	}
+	return b, err
+}
+
+// BoolTF is a flag marshaled as T or F
+type BoolTF bool
+
+// Val returns the bool value
+func (b BoolTF) Val() bool {
+	return bool(b)
+}
+
+// MarshalText writes T or F
+func (b BoolTF) MarshalText() ([]byte, error) {
+	if b {
+		return []byte("T"), nil
+	}
+	return []byte("F"), nil
+}
+
+// UnmarshalText accepts any of intacct's boolean variants.  Unknown
+// values are false.
+func (b *BoolTF) UnmarshalText(text []byte) error {
+	val, err := parseBoolText(string(text), false)
+	*b = BoolTF(val)
+	return err
+}
+
+// BoolYesNo is a flag marshaled as Yes or No
+type BoolYesNo bool
+
+// Val returns the bool value
+func (b BoolYesNo) Val() bool {
+	return bool(b)
+}
+
+// MarshalText writes Yes or No
+func (b BoolYesNo) MarshalText() ([]byte, error) {
+	if b {
+		return []byte("Yes"), nil
+	}
+	return []byte("No"), nil
+}
+
+// UnmarshalText accepts any of intacct's boolean variants.  Unknown
+// values are false.
+func (b *BoolYesNo) UnmarshalText(text []byte) error {
+	val, err := parseBoolText(string(text), false)
+	*b = BoolYesNo(val)
+	return err
+}
+
+// BoolActive is a status flag marshaled as active or inactive
+type BoolActive bool
+
+// Val returns the bool value
+func (b BoolActive) Val() bool {
+	return bool(b)
+}
+
+// MarshalText writes active or inactive
+func (b BoolActive) MarshalText() ([]byte, error) {
+	if b {
+		return []byte("active"), nil
+	}
+	return []byte("inactive"), nil
+}
+
+// UnmarshalText accepts any of intacct's boolean variants.  Unknown
+// values are false.
+func (b *BoolActive) UnmarshalText(text []byte) error {
+	val, err := parseBoolText(string(text), false)
+	*b = BoolActive(val)
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+	"github.com/jfcote87/testutils"
+)
+
+type boolTester struct {
+	XMLName  xml.Name           `xml:"VENDOR"`
+	OnHold   intacct.Bool       `xml:"ONHOLD"`
+	Billable intacct.BoolTF     `xml:"BILLABLE"`
+	Taxable  intacct.BoolYesNo  `xml:"TAXABLE"`
+	Status   intacct.BoolActive `xml:"STATUS"`
+	Hold     intacct.BoolYesNo  `xml:"DISPLAYCONTACT>ONHOLD,omitempty"`
+}
+
+func TestParseBool(t *testing.T) {
+	for _, s := range []string{"true", "T", "Yes", " y ", "1", "Active"} {
+		if b, err := intacct.ParseBool(s); err != nil || !b {
+			t.Errorf("ParseBool(%q) expected true; got %v %v", s, b, err)
+		}
+	}
+	for _, s := range []string{"false", "f", "NO", "n", "0", "inactive"} {
+		if b, err := intacct.ParseBool(s); err != nil || b {
+			t.Errorf("ParseBool(%q) expected false; got %v %v", s, b, err)
+		}
+	}
+	if _, err := intacct.ParseBool("maybe"); err == nil {
+		t.Errorf("ParseBool(maybe) expected error")
+	}
+}
+
+func TestBoolFormats(t *testing.T) {
+	var v boolTester
+	src := `<VENDOR><ONHOLD>T</ONHOLD><BILLABLE>true</BILLABLE><TAXABLE>yes</TAXABLE><STATUS>inactive</STATUS></VENDOR>`
+	if err := xml.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !v.OnHold.Val() || !v.Billable.Val() || !v.Taxable.Val() || v.Status.Val() {
+		t.Errorf("unexpected values %#v", v)
+	}
+	b, err := xml.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	want := `<VENDOR><ONHOLD>true</ONHOLD><BILLABLE>T</BILLABLE><TAXABLE>Yes</TAXABLE><STATUS>inactive</STATUS><DISPLAYCONTACT></DISPLAYCONTACT></VENDOR>`
+	if string(b) != want {
+		t.Errorf("expected %s; got %s", want, b)
+	}
+
+	src = `<VENDOR><ONHOLD>X</ONHOLD><BILLABLE>?</BILLABLE></VENDOR>`
+	if err := xml.Unmarshal([]byte(src), &v); err != nil || v.OnHold.Val() || v.Billable.Val() {
+		t.Errorf("lenient unmarshal expected false values; got %#v %v", v, err)
+	}
+	// strict mode is set per Service
+	ctx := context.Background()
+	for _, tt := range []struct {
+		data    string
+		strict  bool
+		wantErr bool
+	}{
+		{data: src, strict: false},
+		{data: src, strict: true, wantErr: true},
+		{data: `<VENDOR><STATUS>X</STATUS></VENDOR>`, strict: true, wantErr: true},
+		{data: `<VENDOR><ONHOLD></ONHOLD><TAXABLE>N</TAXABLE></VENDOR>`, strict: true},
+		{data: `<VENDOR><NAME>X</NAME><DISPLAYCONTACT><ONHOLD>Y</ONHOLD></DISPLAYCONTACT></VENDOR>`, strict: true},
+		{data: `<VENDOR><DISPLAYCONTACT><ONHOLD>X</ONHOLD></DISPLAYCONTACT></VENDOR>`, strict: true, wantErr: true},
+	} {
+		testTransport := &testutils.Transport{}
+		testTransport.Add(syncTester(queryResponse("VENDOR", 0, tt.data, 1)))
+		sv := &intacct.Service{
+			SenderID:      "SENDERID",
+			Password:      "*******",
+			Authenticator: intacct.SessionID("SESSIONID"),
+			HTTPClientFunc: func(ctx context.Context) (*http.Client, error) {
+				return &http.Client{Transport: testTransport}, nil
+			},
+			StrictBool: tt.strict,
+		}
+		resp, err := sv.Exec(ctx, intacct.Query{Object: "VENDOR", Select: intacct.Select{Fields: []string{"ONHOLD"}}})
+		if err != nil {
+			t.Fatalf("exec: %v", err)
+		}
+		var list []boolTester
+		if err = resp.Decode(&list); (err != nil) != tt.wantErr {
+			t.Errorf("%s strict=%v expected error %v; got %v", tt.data, tt.strict, tt.wantErr, err)
+		}
+	}
+	if err := xml.Unmarshal([]byte(src), &v); err != nil {
+		t.Errorf("unmarshal without a Service expected no error; got %v", err)
+	}
+
+	rm := intacct.ResultMap{"ONHOLD": "Yes", "STATUS": "unknown"}
+	if !rm.Bool("ONHOLD") {
+		t.Errorf("ResultMap.Bool(ONHOLD) expected true")
+	}
+	if _, err := rm.BoolE("STATUS"); err == nil {
+		t.Errorf("ResultMap.BoolE(STATUS) expected error")
+	}
+}
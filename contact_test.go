@@ -0,0 +1,46 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+func TestContactWritable(t *testing.T) {
+	var c *intacct.Contact
+	if c.Writable() != nil {
+		t.Errorf("expected nil writable contact")
+	}
+	src := `<DISPLAYCONTACT><CONTACTNAME>Jane Doe</CONTACTNAME><PRINTAS>Jane</PRINTAS>` +
+		`<WHENMODIFIED>01/02/2020 10:00:00</WHENMODIFIED><MODIFIEDBY>admin</MODIFIEDBY>` +
+		`<MEGAENTITYID>E1</MEGAENTITYID><RECORD_URL>https://example.com</RECORD_URL>` +
+		`<MAILADDRESS><CITY>Austin</CITY></MAILADDRESS></DISPLAYCONTACT>`
+	c = &intacct.Contact{}
+	if err := xml.Unmarshal([]byte(src), c); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	b, err := xml.Marshal(c.Writable())
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	for _, want := range []string{"<CONTACTNAME>Jane Doe</CONTACTNAME>", "<MAILADDRESS><CITY>Austin</CITY></MAILADDRESS>"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %s; got %s", want, b)
+		}
+	}
+	for _, ro := range []string{"2020", "admin", "MEGAENTITYID", "RECORD_URL"} {
+		if strings.Contains(string(b), ro) {
+			t.Errorf("expected %s removed; got %s", ro, b)
+		}
+	}
+	if c.ModifiedBy != "admin" || c.RecordURL == "" {
+		t.Errorf("expected original contact unchanged")
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CustomFields holds the custom fields of an object.  Use it with the
+// ",any" tag to collect elements not matching other struct fields.
+//
+//	type Vendor struct {
+//	    VendorID     string               `xml:"VENDORID,omitempty"`
+//	    CustomFields intacct.CustomFields `xml:",any"`
+//	}
+//
+// Names are matched case insensitively.  Fields marshal in slice order and
+// a repeated name is written once using its last value.  When a struct is
+// the payload of a Writer (e.g. Create or Update), fields whose names match
+// another field of the struct are skipped (see Exclude).
+type CustomFields []CustomField
+
+func (cf CustomFields) index(name string) int {
+	for i, c := range cf {
+		if strings.EqualFold(c.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the value of the named field
+func (cf CustomFields) Get(name string) (string, bool) {
+	if i := cf.index(name); i >= 0 {
+		return cf[i].Value, true
+	}
+	return "", false
+}
+
+// Set replaces the value of the named field keeping its position or
+// appends a new field.
+func (cf *CustomFields) Set(name, value string) {
+	if i := cf.index(name); i >= 0 {
+		(*cf)[i].Value = value
+		return
+	}
+	*cf = append(*cf, CustomField{Name: name, Value: value})
+}
+
+// SetDate sets the named field to t formatted as MM/DD/YYYY
+func (cf *CustomFields) SetDate(name string, t time.Time) {
+	cf.Set(name, t.Format("01/02/2006"))
+}
+
+// SetDecimal sets the named field to d
+func (cf *CustomFields) SetDecimal(name string, d Decimal) {
+	cf.Set(name, d.String())
+}
+
+// SetBool sets the named field to true or false
+func (cf *CustomFields) SetBool(name string, b bool) {
+	cf.Set(name, strconv.FormatBool(b))
+}
+
+// Delete removes the named field
+func (cf *CustomFields) Delete(name string) {
+	if i := cf.index(name); i >= 0 {
+		*cf = append((*cf)[:i], (*cf)[i+1:]...)
+	}
+}
+
+// text returns the trimmed value of a field or a FieldMissing error
+func (cf CustomFields) text(name string) (string, error) {
+	s, ok := cf.Get(name)
+	if s = strings.TrimSpace(s); !ok || s == "" {
+		return "", &FieldError{Path: name, Kind: FieldMissing}
+	}
+	return s, nil
+}
+
+// Date parses the named field as a MM/DD/YYYY or YYYY-MM-DD date
+func (cf CustomFields) Date(name string) (Date, error) {
+	var dx Date
+	s, err := cf.text(name)
+	if err != nil {
+		return dx, err
+	}
+	if err = dx.UnmarshalText([]byte(s)); err != nil {
+		return Date{}, parseError(name, s, fmt.Errorf("expected MM/DD/YYYY"))
+	}
+	return dx, nil
+}
+
+// Decimal parses the named field as a Decimal
+func (cf CustomFields) Decimal(name string) (Decimal, error) {
+	s, err := cf.text(name)
+	if err != nil {
+		return Decimal{}, err
+	}
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return Decimal{}, parseError(name, s, strconv.ErrSyntax)
+	}
+	return d, nil
+}
+
+// Bool parses the named field as a checkbox value
+func (cf CustomFields) Bool(name string) (bool, error) {
+	s, err := cf.text(name)
+	if err != nil {
+		return false, err
+	}
+	b, err := ParseBool(s)
+	if err != nil {
+		return false, parseError(name, s, strconv.ErrSyntax)
+	}
+	return b, nil
+}
+
+// Typed converts the named field using the field's data type in ot.
+// INTEGER returns int64, numeric types Decimal, BOOLEAN bool, DATE Date,
+// TIMESTAMP Datetime and all others string.  Blank values return nil.
+func (cf CustomFields) Typed(name string, ot *ObjectType) (interface{}, error) {
+	fld, ok := ot.Field(name)
+	if !ok || !fld.IsCustom {
+		return nil, fmt.Errorf("%s is not a custom field of %s", name, ot.Name)
+	}
+	s, err := cf.text(name)
+	if err != nil {
+		if IsFieldMissing(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	switch k := kindOf(fld.DataType); {
+	case k == kindInteger:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, parseError(name, s, err)
+		}
+		return i, nil
+	case k.isNumeric():
+		return cf.Decimal(name)
+	case k == kindBool:
+		return cf.Bool(name)
+	case k == kindDate:
+		return cf.Date(name)
+	case k == kindTimestamp:
+		var dt Datetime
+		if err := dt.UnmarshalText([]byte(s)); err != nil {
+			return nil, parseError(name, s, err)
+		}
+		return dt, nil
+	}
+	return s, nil
+}
+
+// Validate checks that each field is a custom field of ot, that its value
+// suits the field's data type and that it is one of the field's valid
+// values.  Blank values clear a field and are not checked.  Problems are
+// returned as QueryErrors.
+func (cf CustomFields) Validate(ot *ObjectType) error {
+	var errs QueryErrors
+	add := func(field, msg string) {
+		errs = append(errs, QueryError{Element: "customfields", Field: field, Message: msg})
+	}
+	for _, c := range cf {
+		fld, ok := ot.Field(c.Name)
+		switch {
+		case !ok:
+			add(c.Name, "unknown field for "+ot.Name)
+			continue
+		case !fld.IsCustom:
+			add(c.Name, "not a custom field")
+			continue
+		case strings.TrimSpace(c.Value) == "":
+			continue
+		}
+		if err := checkValue(kindOf(fld.DataType), c.Value); err != nil {
+			add(c.Name, fmt.Sprintf("invalid value %q: %v", c.Value, err))
+			continue
+		}
+		if len(fld.ValidValues) > 0 && !containsString(fld.ValidValues, c.Value) {
+			add(c.Name, fmt.Sprintf("%q not in valid values %s", c.Value, strings.Join(fld.ValidValues, ", ")))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Exclude returns the fields whose names do not match a field of v,
+// a struct or pointer to a struct.  Writer payloads are excluded
+// automatically; use Exclude before marshaling a struct directly so that
+// a value is not written twice.
+func (cf CustomFields) Exclude(v interface{}) CustomFields {
+	names, err := StructFields(v, nil)
+	if err != nil {
+		return cf
+	}
+	var list CustomFields
+	for _, c := range cf {
+		if !containsFold(names, c.Name) {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+var customFieldsType = reflect.TypeOf(CustomFields(nil))
+
+// excludeCustomFields returns a copy of v, a struct, pointer to a struct
+// or slice of either, whose CustomFields fields exclude names matching
+// the struct's other fields.  ok is false when nothing was excluded and
+// v should be used as is.
+func excludeCustomFields(v reflect.Value) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return v, false
+		}
+		ev, ok := excludeCustomFields(v.Elem())
+		if ok && v.Kind() == reflect.Ptr {
+			return ev.Addr(), true
+		}
+		return ev, ok
+	case reflect.Slice, reflect.Array:
+		var list reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			ev, ok := excludeCustomFields(v.Index(i))
+			if !ok {
+				continue
+			}
+			if !list.IsValid() {
+				list = reflect.New(v.Type()).Elem()
+				if v.Kind() == reflect.Slice {
+					list.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+				}
+				reflect.Copy(list, v)
+			}
+			list.Index(i).Set(ev)
+		}
+		return list, list.IsValid()
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.Type != customFieldsType || f.PkgPath != "" {
+				continue
+			}
+			cf := v.Field(i).Interface().(CustomFields)
+			excl := cf.Exclude(v.Interface())
+			if len(excl) == len(cf) {
+				return v, false
+			}
+			cp := reflect.New(v.Type()).Elem()
+			cp.Set(v)
+			cp.Field(i).Set(reflect.ValueOf(excl))
+			return cp, true
+		}
+	}
+	return v, false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalXML writes each field as <NAME>VALUE</NAME> in slice order.  Fields
+// without a name are skipped and a repeated name is written once at its
+// first position with its last value.
+func (cf CustomFields) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	for i, c := range cf {
+		if c.Name == "" || cf[:i].index(c.Name) >= 0 {
+			continue
+		}
+		for _, later := range cf[i+1:] {
+			if strings.EqualFold(later.Name, c.Name) {
+				c.Value = later.Value
+			}
+		}
+		if err := c.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+)
+
+var customOT = &intacct.ObjectType{
+	Name: "VENDOR",
+	Fields: []intacct.ObjectField{
+		{ID: "VENDORID", DataType: "TEXT"},
+		{ID: "REGION", DataType: "TEXT", IsCustom: true, ValidValues: []string{"East", "West"}},
+		{ID: "REVIEWED", DataType: "BOOLEAN", IsCustom: true},
+		{ID: "REVIEWDATE", DataType: "DATE", IsCustom: true},
+		{ID: "CREDITLIMIT", DataType: "CURRENCY", IsCustom: true},
+		{ID: "RANK", DataType: "INTEGER", IsCustom: true},
+	},
+}
+
+type customVendor struct {
+	XMLName      xml.Name             `xml:"VENDOR"`
+	VendorID     string               `xml:"VENDORID,omitempty"`
+	Region       string               `xml:"REGION,omitempty"`
+	CustomFields intacct.CustomFields `xml:",any"`
+}
+
+func TestCustomFields(t *testing.T) {
+	var v customVendor
+	src := `<VENDOR><VENDORID>V1</VENDORID><REVIEWED>true</REVIEWED><REVIEWDATE>03/15/2020</REVIEWDATE>` +
+		`<CREDITLIMIT>1500.25</CREDITLIMIT><RANK>x</RANK></VENDOR>`
+	if err := xml.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	cf := v.CustomFields
+	if len(cf) != 4 {
+		t.Fatalf("expected 4 custom fields; got %d", len(cf))
+	}
+	if b, err := cf.Bool("reviewed"); err != nil || !b {
+		t.Errorf("Bool expected true; got %v %v", b, err)
+	}
+	if dx, err := cf.Date("REVIEWDATE"); err != nil || dx.String() != "2020-03-15" {
+		t.Errorf("Date expected 2020-03-15; got %v %v", dx, err)
+	}
+	if d, err := cf.Decimal("CREDITLIMIT"); err != nil || d.String() != "1500.25" {
+		t.Errorf("Decimal expected 1500.25; got %v %v", d, err)
+	}
+	if _, err := cf.Decimal("MISSING"); !intacct.IsFieldMissing(err) {
+		t.Errorf("expected missing error; got %v", err)
+	}
+	if val, err := cf.Typed("CREDITLIMIT", customOT); err != nil {
+		t.Errorf("Typed failed: %v", err)
+	} else if d, ok := val.(intacct.Decimal); !ok || d.String() != "1500.25" {
+		t.Errorf("Typed expected Decimal 1500.25; got %#v", val)
+	}
+	if _, err := cf.Typed("RANK", customOT); err == nil {
+		t.Errorf("Typed expected parse error for RANK")
+	}
+
+	err := cf.Validate(customOT)
+	qe, ok := err.(intacct.QueryErrors)
+	if !ok || len(qe) != 1 || qe[0].Field != "RANK" {
+		t.Errorf("Validate expected single RANK error; got %v", err)
+	}
+	cf.Set("RANK", "3")
+	cf.Set("REGION", "North")
+	cf.Set("VENDORID", "V2")
+	qe, _ = cf.Validate(customOT).(intacct.QueryErrors)
+	if len(qe) != 2 || qe[0].Field != "REGION" || qe[1].Field != "VENDORID" {
+		t.Errorf("Validate expected REGION and VENDORID errors; got %v", qe)
+	}
+
+	cf.Delete("REVIEWDATE")
+	cf.SetBool("REVIEWED", false)
+	cf.SetDate("NEXTREVIEW", time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC))
+	v.CustomFields = cf.Exclude(v)
+	v.CustomFields = append(v.CustomFields, intacct.CustomField{Name: "RANK", Value: "4"})
+	b, err := xml.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	want := `<VENDOR><VENDORID>V1</VENDORID><REVIEWED>false</REVIEWED><CREDITLIMIT>1500.25</CREDITLIMIT>` +
+		`<RANK>4</RANK><NEXTREVIEW>01/02/2021</NEXTREVIEW></VENDOR>`
+	if string(b) != want {
+		t.Errorf("expected %s; got %s", want, b)
+	}
+
+	// Writer payloads drop custom fields that duplicate struct fields
+	v = customVendor{VendorID: "V1", Region: "East", CustomFields: intacct.CustomFields{{Name: "region", Value: "West"}, {Name: "RANK", Value: "4"}}}
+	want = `<VENDOR><VENDORID>V1</VENDORID><REGION>East</REGION><RANK>4</RANK></VENDOR>`
+	for _, payload := range []interface{}{v, &v, []customVendor{v}, []*customVendor{&v}} {
+		if b, err = xml.Marshal(intacct.Update("VENDOR", payload)); err != nil || !strings.Contains(string(b), want) {
+			t.Errorf("%T expected %s; got %s %v", payload, want, b, err)
+		}
+	}
+	if len(v.CustomFields) != 2 {
+		t.Errorf("expected payload custom fields to be unchanged; got %v", v.CustomFields)
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact fixed-point number for currency amounts.  The value
+// is coef * 10^-scale.  Decimals are immutable; arithmetic methods return
+// new values.  The zero value is 0.
+//
+// Decimal marshals to text, xml and json in intacct's plain format
+// (e.g. -1234.50) and parsing returns an error rather than a zero
+// value.  The zero value is treated as unset and is not written to
+// xml; use NewDecimal(0, 0) or a parsed value to send 0.  Use String
+// when a Filter value is needed, e.g.
+//
+//	f.GreaterThan("TOTALDUE", amt.String())
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+var bigTen = big.NewInt(10)
+
+// NewDecimal returns coef * 10^-scale, e.g. NewDecimal(12345, 2) is 123.45.
+func NewDecimal(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(coef), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// ParseDecimal parses a plain decimal string such as 1234.56 or -0.5.
+// Exponents, thousands separators and blank strings are errors.
+func ParseDecimal(s string) (Decimal, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 || digits == "" || digits == "." {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", orig)
+	}
+	var scale int32
+	if idx := strings.Index(digits, "."); idx >= 0 {
+		scale = int32(len(digits) - idx - 1)
+		digits = digits[:idx] + digits[idx+1:]
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", orig)
+		}
+	}
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if strings.HasPrefix(s, "-") {
+		coef.Neg(coef)
+	}
+	return Decimal{coef: coef, scale: scale}, nil
+}
+
+// MustParseDecimal is ParseDecimal that panics on error.  It is intended
+// for constants and tests.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) bigCoef() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d at a larger scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	c := d.bigCoef()
+	if scale <= d.scale {
+		return new(big.Int).Set(c)
+	}
+	return new(big.Int).Mul(c, pow10(scale-d.scale))
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.bigCoef().Sign()
+}
+
+// IsZero returns true if d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsNil returns true when d has not been set.  An unset Decimal is 0
+// but is not written to xml.
+func (d Decimal) IsNil() bool {
+	return d.coef == nil
+}
+
+// Cmp compares d and d2 returning -1, 0 or 1.  Scale is ignored so
+// 1.5 and 1.50 are equal.
+func (d Decimal) Cmp(d2 Decimal) int {
+	scale := maxScale(d, d2)
+	return d.rescale(scale).Cmp(d2.rescale(scale))
+}
+
+// Equal returns true when d and d2 have the same value
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+func maxScale(d, d2 Decimal) int32 {
+	if d.scale > d2.scale {
+		return d.scale
+	}
+	return d2.scale
+}
+
+// Add returns d + d2
+func (d Decimal) Add(d2 Decimal) Decimal {
+	scale := maxScale(d, d2)
+	return Decimal{coef: new(big.Int).Add(d.rescale(scale), d2.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - d2
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	scale := maxScale(d, d2)
+	return Decimal{coef: new(big.Int).Sub(d.rescale(scale), d2.rescale(scale)), scale: scale}
+}
+
+// Mul returns d * d2 with a scale of d.Scale() + d2.Scale()
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.bigCoef(), d2.bigCoef()), scale: d.scale + d2.scale}
+}
+
+// Div returns d / d2 rounded half away from zero to places digits.
+// Div panics if d2 is zero.
+func (d Decimal) Div(d2 Decimal, places int32) Decimal {
+	if d2.IsZero() {
+		panic("intacct: Decimal division by zero")
+	}
+	// compute one extra digit then round
+	p := places
+	if p < 0 {
+		p = 0
+	}
+	num := d.rescale(d2.scale + p + 1 + maxScale(d, d2))
+	den := d2.rescale(maxScale(d, d2))
+	q := Decimal{coef: new(big.Int).Quo(num, den), scale: p + 1 + d2.scale}
+	return q.Round(places)
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.bigCoef()), scale: d.scale}
+}
+
+// Abs returns the absolute value of d
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.bigCoef()), scale: d.scale}
+}
+
+// Round rounds half away from zero to places digits after the decimal
+// point (e.g. 2.345 -> 2.35, -2.345 -> -2.35).  Negative places round
+// to tens, hundreds, etc. (e.g. 1250 -> 1300 for -2) with a scale of 0.
+func (d Decimal) Round(places int32) Decimal {
+	return d.round(places, false)
+}
+
+// RoundBankers rounds half to even to places digits after the decimal
+// point (e.g. 2.345 -> 2.34, 2.355 -> 2.36).
+func (d Decimal) RoundBankers(places int32) Decimal {
+	return d.round(places, true)
+}
+
+// Truncate drops digits beyond places digits after the decimal point.
+// As with Round, negative places zero the digits left of the point.
+func (d Decimal) Truncate(places int32) Decimal {
+	if places >= d.scale {
+		return Decimal{coef: d.rescale(places), scale: places}
+	}
+	q := new(big.Int).Quo(d.bigCoef(), pow10(d.scale-places))
+	return Decimal{coef: q, scale: places}.normalize()
+}
+
+func (d Decimal) round(places int32, halfEven bool) Decimal {
+	if places >= d.scale {
+		return Decimal{coef: d.rescale(places), scale: places}
+	}
+	div := pow10(d.scale - places)
+	q, r := new(big.Int).QuoRem(d.bigCoef(), div, new(big.Int))
+	// compare 2*|r| to div to determine rounding direction
+	r2 := new(big.Int).Abs(r)
+	r2.Lsh(r2, 1)
+	cmp := r2.Cmp(div)
+	if cmp > 0 || (cmp == 0 && (!halfEven || q.Bit(0) == 1)) {
+		if d.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{coef: q, scale: places}.normalize()
+}
+
+// normalize returns d with a negative scale moved into the coefficient
+func (d Decimal) normalize() Decimal {
+	if d.scale >= 0 {
+		return d
+	}
+	return Decimal{coef: new(big.Int).Mul(d.bigCoef(), pow10(-d.scale))}
+}
+
+// Float64 returns the nearest float64 value of d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// IntPart returns the integer portion of d
+func (d Decimal) IntPart() int64 {
+	return d.Truncate(0).bigCoef().Int64()
+}
+
+// String returns d in plain format keeping its scale (e.g. 12.50)
+func (d Decimal) String() string {
+	s := new(big.Int).Abs(d.bigCoef()).String()
+	if d.scale > 0 {
+		if len(s) <= int(d.scale) {
+			s = strings.Repeat("0", int(d.scale)-len(s)+1) + s
+		}
+		s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + s
+	}
+	return s
+}
+
+// StringFixed rounds d to places digits and returns the string
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places).String()
+}
+
+// MarshalText formats Decimal for xml
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// MarshalXML writes nothing for the zero value as the amount is unset
+func (d Decimal) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if d.coef == nil {
+		return nil
+	}
+	return e.EncodeElement(d.String(), start)
+}
+
+// UnmarshalText parses the text.  Blank text is zero.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+	val, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = val
+	return nil
+}
+
+// MarshalJSON writes d as a json number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a json number or string.  null and "" are zero.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(bytes.TrimSpace(b))
+	if s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	if len(s) > 1 && s[0] == '"' {
+		unq, err := strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+		s = unq
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// DecimalE parses the value at path as a Decimal
+func (rm ResultMap) DecimalE(path string) (Decimal, error) {
+	s, err := rm.StringE(path)
+	if err != nil {
+		return Decimal{}, err
+	}
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return Decimal{}, parseError(path, s, strconv.ErrSyntax)
+	}
+	return d, nil
+}
+
+// Decimal returns the value at path as a Decimal, zero if missing or
+// invalid.  Use DecimalE to detect errors.
+func (rm ResultMap) Decimal(path string) Decimal {
+	d, _ := rm.DecimalE(path)
+	return d
+}
+
+// Decimal returns the Decimal value at path
+func (fc *FieldCollector) Decimal(path string) Decimal {
+	d, err := fc.rm.DecimalE(path)
+	fc.add(err)
+	return d
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+func TestDecimal(t *testing.T) {
+	d := intacct.MustParseDecimal
+	tests := []struct {
+		name string
+		got  intacct.Decimal
+		want string
+	}{
+		{"parse", d("-1234.50"), "-1234.50"},
+		{"parse leading dot", d(".5"), "0.5"},
+		{"new", intacct.NewDecimal(12345, 2), "123.45"},
+		{"new small", intacct.NewDecimal(-5, 3), "-0.005"},
+		{"new negative scale", intacct.NewDecimal(12, -2), "1200"},
+		{"add", d("0.1").Add(d("0.2")), "0.3"},
+		{"sub", d("10").Sub(d("0.01")), "9.99"},
+		{"mul", d("19.99").Mul(d("3")), "59.97"},
+		{"div", d("10").Div(d("3"), 2), "3.33"},
+		{"div round", d("2").Div(d("3"), 2), "0.67"},
+		{"div negative", d("-1").Div(d("8"), 2), "-0.13"},
+		{"round half up", d("2.345").Round(2), "2.35"},
+		{"round negative", d("-2.345").Round(2), "-2.35"},
+		{"round down", d("2.344").Round(2), "2.34"},
+		{"bankers even", d("2.345").RoundBankers(2), "2.34"},
+		{"bankers odd", d("2.355").RoundBankers(2), "2.36"},
+		{"bankers above half", d("2.3451").RoundBankers(2), "2.35"},
+		{"round extend", d("2.5").Round(2), "2.50"},
+		{"truncate", d("-2.349").Truncate(2), "-2.34"},
+		{"round tens", d("1250.75").Round(-2), "1300"},
+		{"round negative tens", d("-1249.5").Round(-2), "-1200"},
+		{"bankers tens", d("1250").RoundBankers(-2), "1200"},
+		{"truncate tens", d("-1299.99").Truncate(-2), "-1200"},
+		{"div tens", d("10000").Div(d("3"), -1), "3330"},
+		{"abs", d("-3.10").Abs(), "3.10"},
+		{"zero value", intacct.Decimal{}, "0"},
+		{"zero add", intacct.Decimal{}.Add(d("1.00")), "1.00"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%s: expected %s; got %s", tt.name, tt.want, tt.got.String())
+		}
+	}
+	if r := d("1250.75").Round(-2); r.Scale() != 0 || !r.Equal(intacct.NewDecimal(13, -2)) {
+		t.Errorf("expected 1300 with scale 0; got %s scale %d", r, r.Scale())
+	}
+	if !d("1.5").Equal(d("1.50")) || d("1.5").Cmp(d("1.49")) != 1 || d("-1").Sign() != -1 {
+		t.Errorf("comparison failed")
+	}
+	for _, s := range []string{"", "1,234.50", "1e5", "--1", "1.2.3", ".", "abc"} {
+		if _, err := intacct.ParseDecimal(s); err == nil {
+			t.Errorf("ParseDecimal(%q) expected error", s)
+		}
+	}
+
+	type bill struct {
+		XMLName  xml.Name        `xml:"APBILL" json:"-"`
+		TotalDue intacct.Decimal `xml:"TOTALDUE" json:"total_due"`
+		Paid     intacct.Decimal `xml:"TOTALPAID" json:"total_paid"`
+	}
+	var b bill
+	if err := xml.Unmarshal([]byte(`<APBILL><TOTALDUE>1000.10</TOTALDUE><TOTALPAID></TOTALPAID></APBILL>`), &b); err != nil {
+		t.Fatalf("xml unmarshal: %v", err)
+	}
+	if b.TotalDue.String() != "1000.10" || !b.Paid.IsZero() {
+		t.Errorf("expected 1000.10 and 0; got %s %s", b.TotalDue, b.Paid)
+	}
+	if err := xml.Unmarshal([]byte(`<APBILL><TOTALDUE>1,000.10</TOTALDUE></APBILL>`), &b); err == nil {
+		t.Errorf("expected xml unmarshal error for 1,000.10")
+	}
+	if !(intacct.Decimal{}).IsNil() || intacct.NewDecimal(0, 0).IsNil() {
+		t.Errorf("expected only unset decimal to be nil")
+	}
+	x, _ := xml.Marshal(bill{TotalDue: d("5.25")})
+	if string(x) != `<APBILL><TOTALDUE>5.25</TOTALDUE></APBILL>` {
+		t.Errorf("expected unset TOTALPAID to be omitted; got %s", x)
+	}
+	x, _ = xml.Marshal(bill{TotalDue: d("-0.50"), Paid: intacct.NewDecimal(0, 0)})
+	if string(x) != `<APBILL><TOTALDUE>-0.50</TOTALDUE><TOTALPAID>0</TOTALPAID></APBILL>` {
+		t.Errorf("expected TOTALPAID 0; got %s", x)
+	}
+	j, _ := json.Marshal(bill{TotalDue: d("-5.25")})
+	if string(j) != `{"total_due":-5.25,"total_paid":0}` {
+		t.Errorf("unexpected json %s", j)
+	}
+	if err := json.Unmarshal([]byte(`{"total_due":"12.30","total_paid":7.5}`), &b); err != nil || b.TotalDue.String() != "12.30" || b.Paid.String() != "7.5" {
+		t.Errorf("json unmarshal expected 12.30 and 7.5; got %s %s %v", b.TotalDue, b.Paid, err)
+	}
+
+	rm := intacct.ResultMap{"TOTALDUE": "99.95", "BAD": "9,95"}
+	if rm.Decimal("TOTALDUE").String() != "99.95" {
+		t.Errorf("ResultMap.Decimal expected 99.95; got %s", rm.Decimal("TOTALDUE"))
+	}
+	if _, err := rm.DecimalE("BAD"); err == nil {
+		t.Errorf("expected DecimalE error for 9,95")
+	}
+}
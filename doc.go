@@ -0,0 +1,41 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package intacct implements the intacct webservices protocol
+// for accessing intacct data
+// https://developer.intacct.com/web-services/
+//
+// # Saved Queries
+//
+// A Query may be saved and shared as json or yaml.  The serialized form is
+//
+//	{
+//	  "object": "VENDOR",
+//	  "select": {"fields": ["VENDORID", "NAME"], "sum": "TOTALDUE"},
+//	  "filter": {"op": "and", "filters": [
+//	    {"op": "equalto", "field": "STATUS", "values": ["active"]},
+//	    {"op": "greaterthan", "field": "TOTALDUE", "values": ["0"]}
+//	  ]},
+//	  "orderby": {"order": [{"field": "NAME", "descending": true}]},
+//	  "options": {"caseinsensitive": true, "showprivate": false},
+//	  "pagesize": 100,
+//	  "offset": 0,
+//	  "docparid": ""
+//	}
+//
+// Select may also contain "count", "avg", "min" and "max" fields and
+// "count_fields", "avg_fields", "min_fields", "max_fields" and
+// "sum_fields" lists.  Filter ops are and, or, equalto, notequalto,
+// lessthan, lessthanorequalto, greaterthan, greaterthanorequalto, between,
+// in, notin, like, notlike, isnull and isnotnull.  The top level filter
+// may omit op.  Decoding restores Filter parent links.
+//
+// QueryFromJSON loads and validates a json query.  Decoding with
+// json.Unmarshal or yaml.Unmarshal does not validate, so incomplete queries
+// may be loaded; call Query.Validate before using them.  There is no
+// QueryFromYAML because the package does not depend on a yaml library.
+// Query and Filter implement the yaml.v2 Unmarshaler interface, which
+// yaml.v3 also accepts, so either version may be used.
+package intacct // import "github.com/jfcote87/intacct"
@@ -0,0 +1,64 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// genobject -enums generates a string type with a Valid method for each
+// field with valid values.  A Service with StrictEnum set calls checkEnums
+// on each function before encoding a request and on each value decoded by
+// Result.Decode.
+
+// EnumError reports a value that is not one of a field's valid values
+type EnumError struct {
+	Type  string
+	Value string
+}
+
+// Error fulfills the error interface
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("%q is not a valid %s", e.Value, e.Type)
+}
+
+// enumValue is implemented by genobject enum types
+type enumValue interface {
+	Valid() bool
+}
+
+// checkEnums walks v's exported fields, elements and interfaces returning
+// an *EnumError for the first string value whose Valid method returns false.
+func checkEnums(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		if ev, ok := v.Interface().(enumValue); ok && !ev.Valid() {
+			return &EnumError{Type: v.Type().Name(), Value: v.String()}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return checkEnums(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkEnums(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			if err := checkEnums(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+	"github.com/jfcote87/testutils"
+)
+
+// VendorPaymentPriority has the form of a genobject -enums type
+type VendorPaymentPriority string
+
+const (
+	VendorPaymentPriorityHigh VendorPaymentPriority = "High"
+	VendorPaymentPriorityLow  VendorPaymentPriority = "Low"
+)
+
+func (v VendorPaymentPriority) Valid() bool {
+	switch v {
+	case "", VendorPaymentPriorityHigh, VendorPaymentPriorityLow:
+		return true
+	}
+	return false
+}
+
+type enumTester struct {
+	XMLName  xml.Name              `xml:"VENDOR"`
+	Priority VendorPaymentPriority `xml:"PAYMENTPRIORITY,omitempty"`
+}
+
+func TestCheckEnum(t *testing.T) {
+	v := enumTester{Priority: "Urgent"}
+	if b, err := xml.Marshal(v); err != nil || string(b) != "<VENDOR><PAYMENTPRIORITY>Urgent</PAYMENTPRIORITY></VENDOR>" {
+		t.Errorf("lenient marshal expected Urgent; got %s %v", b, err)
+	}
+	if b, err := xml.Marshal(enumTester{}); err != nil || string(b) != "<VENDOR></VENDOR>" {
+		t.Errorf("expected blank value omitted; got %s %v", b, err)
+	}
+	src := "<VENDOR><PAYMENTPRIORITY>Urgent</PAYMENTPRIORITY></VENDOR>"
+	if err := xml.Unmarshal([]byte(src), &v); err != nil || v.Priority != "Urgent" {
+		t.Errorf("lenient unmarshal expected Urgent; got %v", err)
+	}
+
+	// strict mode is set per Service
+	ctx := context.Background()
+	newService := func(strict bool, body []byte) *intacct.Service {
+		testTransport := &testutils.Transport{}
+		testTransport.Add(syncTester(body))
+		return &intacct.Service{
+			SenderID:      "SENDERID",
+			Password:      "*******",
+			Authenticator: intacct.SessionID("SESSIONID"),
+			HTTPClientFunc: func(ctx context.Context) (*http.Client, error) {
+				return &http.Client{Transport: testTransport}, nil
+			},
+			StrictEnum: strict,
+		}
+	}
+	sv := newService(true, nil)
+	if _, err := sv.Exec(ctx, intacct.Create("", v)); err == nil || !strings.Contains(err.Error(), `"Urgent" is not a valid VendorPaymentPriority`) {
+		t.Errorf("strict request expected EnumError; got %v", err)
+	}
+	sv = newService(true, nil)
+	if _, err := sv.Exec(ctx, intacct.Create("", []*enumTester{{Priority: "High"}, {Priority: "Urgent"}})); err == nil {
+		t.Errorf("strict request expected EnumError for slice payload")
+	}
+	for _, strict := range []bool{false, true} {
+		sv = newService(strict, queryResponse("VENDOR", 0, src, 1))
+		resp, err := sv.Exec(ctx, intacct.Query{Object: "VENDOR", Select: intacct.Select{Fields: []string{"PAYMENTPRIORITY"}}})
+		if err != nil {
+			t.Fatalf("exec: %v", err)
+		}
+		var rec enumTester
+		err = resp.Results[0].Decode(&rec)
+		if ee, ok := err.(*intacct.EnumError); strict != ok || (ok && (ee.Value != "Urgent" || ee.Type != "VendorPaymentPriority")) {
+			t.Errorf("strict=%v unexpected decode result %v", strict, err)
+		}
+		if rec.Priority != "Urgent" {
+			t.Errorf("strict=%v expected Urgent; got %q", strict, rec.Priority)
+		}
+		var list []enumTester
+		if err = resp.Results[0].Decode(&list); (err != nil) != strict {
+			t.Errorf("strict=%v unexpected slice decode result %v", strict, err)
+		}
+	}
+	if err := xml.Unmarshal([]byte(src), &v); err != nil {
+		t.Errorf("unmarshal without a Service expected no error; got %v", err)
+	}
+}
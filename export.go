@@ -0,0 +1,371 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportColumn describes a column of exported data.  DataType is the
+// Lookup DATATYPE of the field and is blank when unknown.
+type ExportColumn struct {
+	Name     string
+	DataType string
+}
+
+func (c ExportColumn) kind() fieldKind {
+	return kindOf(c.DataType)
+}
+
+// RowWriter writes exported records.  Row values are nil (blank), string,
+// Int, Float64, Bool, Date, Datetime or []string (repeated elements).
+type RowWriter interface {
+	WriteHeader(cols []ExportColumn) error
+	WriteRow(vals []interface{}) error
+	Flush() error
+}
+
+// Exporter reads all records of a query and writes them to a RowWriter.
+// Columns and value types are determined by the object's Lookup
+// definition.  Nested values are flattened to dotted column names
+// (e.g. DISPLAYCONTACT.EMAIL1), and custom fields are exported using
+// their field IDs.
+type Exporter struct {
+	Service *Service
+	// Query selects the records to export.  When no fields or aggregates
+	// are selected, all fields of the object definition are exported.
+	Query Query
+	// ObjectType is the object definition.  When nil, the Lookup function
+	// is called.
+	ObjectType *ObjectType
+	// PageSize is the number of records read per call (default 1000)
+	PageSize int
+}
+
+// NewExporter returns an Exporter of all records of the object
+func NewExporter(sv *Service, objectName string) *Exporter {
+	return &Exporter{
+		Service: sv,
+		Query:   Query{Object: objectName},
+	}
+}
+
+func (e *Exporter) objectType(ctx context.Context) (*ObjectType, error) {
+	if e.ObjectType != nil {
+		return e.ObjectType, nil
+	}
+	resp, err := e.Service.Exec(ctx, Lookup{ObjectName: e.Query.Object})
+	if err != nil {
+		return nil, err
+	}
+	var ot *ObjectType
+	if err = resp.Decode(&ot); err != nil {
+		return nil, err
+	}
+	if ot == nil {
+		return nil, errors.New("no definition returned for " + e.Query.Object)
+	}
+	e.ObjectType = ot
+	return ot, nil
+}
+
+// Columns returns the columns of the export.  Aggregate columns are named
+// FUNCTION.FIELD (e.g. SUM.TOTALDUE).
+func (e *Exporter) Columns(ctx context.Context) ([]ExportColumn, error) {
+	ot, err := e.objectType(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sel := e.Query.Select
+	if len(sel.Fields) == 0 && !sel.HasAggregates() {
+		cols := make([]ExportColumn, 0, len(ot.Fields))
+		for _, f := range ot.Fields {
+			cols = append(cols, ExportColumn{Name: f.ID, DataType: f.DataType})
+		}
+		return cols, nil
+	}
+	var cols []ExportColumn
+	sx := sel.xmlForm()
+	for _, nm := range sel.Fields {
+		fld, _ := ot.Field(nm)
+		cols = append(cols, ExportColumn{Name: nm, DataType: fld.DataType})
+	}
+	for _, agg := range []struct {
+		fn     string
+		fields []string
+	}{
+		{"COUNT", sx.Count}, {"AVG", sx.Avg}, {"MIN", sx.Min}, {"MAX", sx.Max}, {"SUM", sx.Sum},
+	} {
+		for _, nm := range agg.fields {
+			dataType := "DECIMAL"
+			switch agg.fn {
+			case "COUNT":
+				dataType = "INTEGER"
+			case "MIN", "MAX":
+				fld, _ := ot.Field(nm)
+				dataType = fld.DataType
+			}
+			cols = append(cols, ExportColumn{Name: agg.fn + "." + nm, DataType: dataType})
+		}
+	}
+	return cols, nil
+}
+
+// Export writes the header and all records to w returning the number of
+// records written.
+func (e *Exporter) Export(ctx context.Context, w RowWriter) (int, error) {
+	if e.Service == nil || e.Query.Object == "" {
+		return 0, errors.New("exporter requires Service and Query.Object")
+	}
+	cols, err := e.Columns(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err = w.WriteHeader(cols); err != nil {
+		return 0, err
+	}
+	q := e.Query
+	if len(q.Select.Fields) == 0 && !q.Select.HasAggregates() {
+		for _, c := range cols {
+			q.Select.Fields = append(q.Select.Fields, c.Name)
+		}
+	}
+	if q.PageSz = e.PageSize; q.PageSz <= 0 {
+		q.PageSz = 1000
+	}
+	loc := e.Service.Location()
+	var cnt int
+	numRemaining := -1
+	for numRemaining != 0 {
+		resp, err := e.Service.Exec(ctx, q)
+		if err != nil {
+			return cnt, err
+		}
+		var recs []ResultMap
+		if err = resp.Decode(&recs); err != nil {
+			return cnt, err
+		}
+		if len(resp.Results) == 0 || resp.Results[0].Data == nil {
+			return cnt, errors.New("empty result returned")
+		}
+		for _, rec := range recs {
+			if err = w.WriteRow(exportRow(cols, rec, loc)); err != nil {
+				return cnt, err
+			}
+			cnt++
+		}
+		if len(recs) == 0 {
+			break
+		}
+		numRemaining = resp.Results[0].Data.NumRemaining
+		q.Offset += q.PageSz
+	}
+	return cnt, w.Flush()
+}
+
+// ExportCSV writes all records of the object to w in csv format
+func ExportCSV(ctx context.Context, sv *Service, objectName string, w io.Writer) (int, error) {
+	return NewExporter(sv, objectName).Export(ctx, NewCSVRowWriter(w))
+}
+
+// ExportNDJSON writes all records of the object to w as newline delimited json
+func ExportNDJSON(ctx context.Context, sv *Service, objectName string, w io.Writer) (int, error) {
+	return NewExporter(sv, objectName).Export(ctx, NewNDJSONRowWriter(w))
+}
+
+// exportRow returns the typed values of rec in column order.  Timestamps
+// without an offset are read in loc.
+func exportRow(cols []ExportColumn, rec ResultMap, loc *time.Location) []interface{} {
+	flat := make(map[string]interface{})
+	flattenResultMap("", rec, flat)
+	vals := make([]interface{}, len(cols))
+	for i, c := range cols {
+		vals[i] = exportValue(c.kind(), flat[c.Name], loc)
+	}
+	return vals
+}
+
+// flattenResultMap copies the values of rm into flat using dotted names
+// for nested elements.  Attributes and repeated nested elements are
+// not exported.
+func flattenResultMap(prefix string, rm ResultMap, flat map[string]interface{}) {
+	for k, v := range rm {
+		if strings.HasPrefix(k, "@") {
+			continue
+		}
+		nm := prefix + k
+		if k == "" {
+			nm = strings.TrimSuffix(prefix, ".")
+		}
+		switch val := v.(type) {
+		case ResultMap:
+			flattenResultMap(nm+".", val, flat)
+		case string, []string:
+			flat[nm] = val
+		}
+	}
+}
+
+// exportValue converts a string value to the column's type.  Values that
+// do not parse are returned as strings.
+func exportValue(k fieldKind, v interface{}, loc *time.Location) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	if s == "" {
+		return nil
+	}
+	switch k {
+	case kindInteger:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return Int(i)
+		}
+	case kindNumber:
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return Float64(f)
+		}
+	case kindBool:
+		if b, err := ParseBool(s); err == nil {
+			return Bool(b)
+		}
+	case kindDate:
+		var dt Date
+		if err := dt.UnmarshalText([]byte(s)); err == nil && !dt.IsNil() {
+			return dt
+		}
+	case kindTimestamp:
+		var dt Datetime
+		if err := dt.UnmarshalText([]byte(s)); err == nil && !dt.IsNil() {
+			return TimeToDatetime(*dt.In(loc))
+		}
+	}
+	return s
+}
+
+// formatExportValue returns the text form of an export value.  Dates are
+// formatted YYYY-MM-DD and timestamps in RFC3339 format with the offset
+// of the Service's time zone.
+func formatExportValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case Bool:
+		return strconv.FormatBool(bool(val))
+	case []string:
+		return strings.Join(val, ";")
+	case interface{ String() string }:
+		return val.String()
+	}
+	return ""
+}
+
+// CSVRowWriter writes exported rows in csv format.  Repeated values are
+// separated by semicolons.
+type CSVRowWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVRowWriter returns a RowWriter writing to w
+func NewCSVRowWriter(w io.Writer) *CSVRowWriter {
+	return &CSVRowWriter{w: csv.NewWriter(w)}
+}
+
+// WriteHeader writes the column names
+func (cw *CSVRowWriter) WriteHeader(cols []ExportColumn) error {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.Name
+	}
+	return cw.w.Write(names)
+}
+
+// WriteRow writes a record
+func (cw *CSVRowWriter) WriteRow(vals []interface{}) error {
+	rec := make([]string, len(vals))
+	for i, v := range vals {
+		rec[i] = formatExportValue(v)
+	}
+	return cw.w.Write(rec)
+}
+
+// Flush writes buffered data
+func (cw *CSVRowWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// NDJSONRowWriter writes each exported row as a json object on a single
+// line.  Keys are written in column order, blank values are null and
+// numbers and bools are unquoted.
+type NDJSONRowWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+}
+
+// NewNDJSONRowWriter returns a RowWriter writing to w
+func NewNDJSONRowWriter(w io.Writer) *NDJSONRowWriter {
+	return &NDJSONRowWriter{w: bufio.NewWriter(w)}
+}
+
+// WriteHeader saves the column names for use as object keys
+func (nw *NDJSONRowWriter) WriteHeader(cols []ExportColumn) error {
+	nw.keys = make([][]byte, len(cols))
+	for i, c := range cols {
+		b, err := json.Marshal(c.Name)
+		if err != nil {
+			return err
+		}
+		nw.keys[i] = b
+	}
+	return nil
+}
+
+// WriteRow writes a record
+func (nw *NDJSONRowWriter) WriteRow(vals []interface{}) error {
+	nw.w.WriteByte('{')
+	for i, v := range vals {
+		if i > 0 {
+			nw.w.WriteByte(',')
+		}
+		nw.w.Write(nw.keys[i])
+		nw.w.WriteByte(':')
+		var b []byte
+		var err error
+		switch val := v.(type) {
+		case nil:
+			b = []byte("null")
+		case Int, Float64:
+			b = []byte(formatExportValue(val))
+		case Bool:
+			b, err = json.Marshal(bool(val))
+		case []string:
+			b, err = json.Marshal(val)
+		default:
+			b, err = json.Marshal(formatExportValue(val))
+		}
+		if err != nil {
+			return err
+		}
+		nw.w.Write(b)
+	}
+	nw.w.WriteString("}\n")
+	return nil
+}
+
+// Flush writes buffered data
+func (nw *NDJSONRowWriter) Flush() error {
+	return nw.w.Flush()
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+	"github.com/jfcote87/testutils"
+)
+
+const exportRecords = `<VENDOR><RECORDNO>1</RECORDNO><VENDORID>V1</VENDORID><NAME>Acme, Inc.</NAME><STATUS>active</STATUS>` +
+	`<TOTALDUE>125.50</TOTALDUE><ONHOLD>false</ONHOLD><WHENMODIFIED>01/02/2021 10:00:00</WHENMODIFIED>` +
+	`<LASTPAID>12/31/2020</LASTPAID><REGION>West</REGION><TERM><NAME>N30</NAME></TERM></VENDOR>` +
+	`<VENDOR><RECORDNO>2</RECORDNO><VENDORID>V2</VENDORID><NAME>Bolt "Co"</NAME><STATUS>inactive</STATUS>` +
+	`<TOTALDUE></TOTALDUE><ONHOLD>true</ONHOLD><WHENMODIFIED>01/03/2021 11:30:00</WHENMODIFIED>` +
+	`<LASTPAID></LASTPAID><REGION></REGION></VENDOR>`
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	testTransport := &testutils.Transport{}
+	for i := 0; i < 3; i++ {
+		testTransport.Add(&testutils.RequestTester{
+			Method:   "POST",
+			Response: testutils.MakeResponse(200, queryResponse("VENDOR", 0, exportRecords, 2), xmlHeader),
+		})
+	}
+	sv := &intacct.Service{
+		SenderID:      "SENDERID",
+		Password:      "*******",
+		Authenticator: intacct.SessionID("SESSIONID"),
+		HTTPClientFunc: func(ctx context.Context) (*http.Client, error) {
+			return &http.Client{Transport: testTransport}, nil
+		},
+	}
+	ot := getVendorLookup(t)
+
+	var buf bytes.Buffer
+	ex := &intacct.Exporter{Service: sv, Query: intacct.Query{Object: "VENDOR"}, ObjectType: ot}
+	cnt, err := ex.Export(ctx, intacct.NewCSVRowWriter(&buf))
+	if err != nil || cnt != 2 {
+		t.Fatalf("csv export expected 2 records; got %d %v", cnt, err)
+	}
+	wantCSV := "RECORDNO,VENDORID,NAME,STATUS,TOTALDUE,ONHOLD,WHENMODIFIED,LASTPAID,REGION\n" +
+		"1,V1,\"Acme, Inc.\",active,125.5,false,2021-01-02T10:00:00Z,2020-12-31,West\n" +
+		"2,V2,\"Bolt \"\"Co\"\"\",inactive,,true,2021-01-03T11:30:00Z,,\n"
+	if buf.String() != wantCSV {
+		t.Errorf("expected csv\n%s; got\n%s", wantCSV, buf.String())
+	}
+
+	// timestamps are read in the service's time zone
+	sv.TimeZone = time.FixedZone("EST", -5*60*60)
+	buf.Reset()
+	ex.Query.Select = intacct.Select{Fields: []string{"RECORDNO", "WHENMODIFIED"}}
+	if _, err = ex.Export(ctx, intacct.NewCSVRowWriter(&buf)); err != nil {
+		t.Fatalf("csv export with time zone: %v", err)
+	}
+	if want := "RECORDNO,WHENMODIFIED\n1,2021-01-02T10:00:00-05:00\n2,2021-01-03T11:30:00-05:00\n"; buf.String() != want {
+		t.Errorf("expected csv\n%s; got\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	ex.Query.Select = intacct.Select{Fields: []string{"RECORDNO", "TERM.NAME", "TOTALDUE", "LASTPAID"}}
+	if _, err = ex.Export(ctx, intacct.NewNDJSONRowWriter(&buf)); err != nil {
+		t.Fatalf("ndjson export: %v", err)
+	}
+	wantJSON := `{"RECORDNO":1,"TERM.NAME":"N30","TOTALDUE":125.5,"LASTPAID":"2020-12-31"}` + "\n" +
+		`{"RECORDNO":2,"TERM.NAME":null,"TOTALDUE":null,"LASTPAID":null}` + "\n"
+	if buf.String() != wantJSON {
+		t.Errorf("expected ndjson\n%s; got\n%s", wantJSON, buf.String())
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"encoding"
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	xmlMarshalerType  = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// StructFields returns the list of field names for use in Query.Select or
+// Reader.Fields based upon the xml tags of v, which must be a struct, a
+// pointer to a struct or a slice of structs. Nested structs (e.g. Contact's
+// MailAddress) are returned as dotted names such as MAILADDRESS.CITY.
+//
+// When ot is not nil and the struct contains an ",any" field (e.g.
+// CustomFields CustomFields), custom fields from ot that are not
+// explicitly defined in the struct are appended to the list.
+//
+// A field whose type is a struct already being walked (e.g. Child *Node
+// in type Node) is skipped, so self-referential types do not recurse.
+func StructFields(v interface{}, ot *ObjectType) ([]string, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("StructFields requires a struct, pointer to a struct or a slice of structs")
+	}
+	sf := &structFields{names: make(map[string]bool), visiting: make(map[reflect.Type]bool)}
+	sf.walk(t, "", 0)
+	if sf.hasAny && ot != nil {
+		for _, f := range ot.Fields {
+			if f.IsCustom {
+				sf.add(f.ID)
+			}
+		}
+	}
+	return sf.list, nil
+}
+
+type structFields struct {
+	list     []string
+	names    map[string]bool
+	hasAny   bool
+	visiting map[reflect.Type]bool // struct types on the current path
+}
+
+func (sf *structFields) add(nm string) {
+	if key := strings.ToUpper(nm); !sf.names[key] {
+		sf.names[key] = true
+		sf.list = append(sf.list, nm)
+	}
+}
+
+func (sf *structFields) walk(t reflect.Type, prefix string, depth int) {
+	if sf.visiting[t] {
+		return
+	}
+	sf.visiting[t] = true
+	defer delete(sf.visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if (f.PkgPath != "" && !f.Anonymous) || f.Name == "XMLName" {
+			continue
+		}
+		tag := f.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		nm := opts[0]
+		if isSpecialXMLField(opts[1:]) {
+			if depth == 0 && hasOption(opts[1:], "any") {
+				sf.hasAny = true
+			}
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr || (ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8) {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && nm == "" && ft.Kind() == reflect.Struct {
+			sf.walk(ft, prefix, depth)
+			continue
+		}
+		if nm == "" {
+			nm = f.Name
+		}
+		nm = prefix + strings.Replace(nm, ">", ".", -1)
+		if ft.Kind() == reflect.Struct && !isLeafType(ft) {
+			sf.walk(ft, nm+".", depth+1)
+			continue
+		}
+		sf.add(nm)
+	}
+}
+
+// isSpecialXMLField returns true for attr, chardata, innerxml, comment,
+// cdata and any fields, none of which are intacct fields.
+func isSpecialXMLField(opts []string) bool {
+	for _, o := range opts {
+		switch o {
+		case "attr", "chardata", "innerxml", "comment", "cdata", "any":
+			return true
+		}
+	}
+	return false
+}
+
+func hasOption(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isLeafType returns true for struct types that marshal to a single value
+// such as Date and Datetime.
+func isLeafType(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	pt := reflect.PtrTo(t)
+	return t.Implements(textMarshalerType) || pt.Implements(textMarshalerType) ||
+		t.Implements(xmlMarshalerType) || pt.Implements(xmlMarshalerType)
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+type fieldsTester struct {
+	XMLName  xml.Name     `xml:"VENDOR"`
+	ID       string       `xml:"VENDORID,omitempty"`
+	Name     string       `xml:"NAME"`
+	Status   string       `xml:"status,attr"`
+	Modfied  intacct.Date `xml:"WHENMODIFIED,omitempty"`
+	Untagged string
+	Ignore   string `xml:"-"`
+	ignore   string
+	Term     *struct {
+		Name string `xml:"NAME"`
+	} `xml:"TERM"`
+	Path         string                `xml:"DISPLAYCONTACT>EMAIL1"`
+	Contacts     []intacct.MailAddress `xml:"CONTACTS,omitempty"`
+	CustomFields []intacct.CustomField `xml:",any"`
+}
+
+type fieldsNode struct {
+	Name     string        `xml:"NAME"`
+	Child    *fieldsNode   `xml:"CHILD"`
+	Children []fieldsNode  `xml:"CHILDREN"`
+	Term     *fieldsBranch `xml:"TERM"`
+}
+
+type fieldsBranch struct {
+	Name string      `xml:"NAME"`
+	Node *fieldsNode `xml:"NODE"`
+}
+
+func TestStructFields(t *testing.T) {
+	ot := getVendorLookup(t)
+	tests := []struct {
+		name string
+		v    interface{}
+		ot   *intacct.ObjectType
+		want []string
+	}{
+		{
+			name: "tester",
+			v:    &fieldsTester{},
+			ot:   ot,
+			want: []string{"VENDORID", "NAME", "WHENMODIFIED", "Untagged", "TERM.NAME", "DISPLAYCONTACT.EMAIL1",
+				"CONTACTS.ADDRESS1", "CONTACTS.ADDRESS2", "CONTACTS.CITY", "CONTACTS.STATE", "CONTACTS.ZIP",
+				"CONTACTS.COUNTRY", "CONTACTS.COUNTRYCODE", "CONTACTS.LATITUDE", "CONTACTS.LONGITUDE", "REGION"},
+		},
+		{
+			name: "self referential",
+			v:    fieldsNode{},
+			want: []string{"NAME", "TERM.NAME"},
+		},
+		{
+			name: "no schema",
+			v:    []intacct.MailAddress{},
+			want: []string{"ADDRESS1", "ADDRESS2", "CITY", "STATE", "ZIP", "COUNTRY", "COUNTRYCODE", "LATITUDE", "LONGITUDE"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := intacct.StructFields(tt.v, tt.ot)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v; got %v", tt.name, tt.want, got)
+		}
+	}
+
+	flds, _ := intacct.StructFields(intacct.Contact{}, nil)
+	if len(flds) != 45 || flds[0] != "RECORDNO" || flds[36] != "MAILADDRESS.ADDRESS1" {
+		t.Errorf("expected 45 Contact fields with MAILADDRESS.ADDRESS1; got %d %v", len(flds), flds)
+	}
+	if _, err := intacct.StructFields("string", nil); err == nil {
+		t.Errorf("expected error for non-struct value")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -203,12 +204,17 @@ func (w Writer) MarshalXML(e *xml.Encoder, s xml.StartElement) error {
 	return e.EncodeToken(xml.EndElement{Name: s.Name})
 }
 
-// only call if w.Payload != nil
+// only call if w.Payload != nil.  Custom fields duplicating a field of
+// the payload struct are dropped.
 func (w *Writer) encodePayload(e *xml.Encoder) error {
+	payload := w.Payload
+	if v, ok := excludeCustomFields(reflect.ValueOf(payload)); ok {
+		payload = v.Interface()
+	}
 	if w.ObjectName == "" {
-		return e.Encode(w.Payload)
+		return e.Encode(payload)
 	}
-	return e.EncodeElement(w.Payload, xml.StartElement{Name: xml.Name{Local: w.ObjectName}})
+	return e.EncodeElement(payload, xml.StartElement{Name: xml.Name{Local: w.ObjectName}})
 }
 
 // Create returns a Writer function to create object(s) in payload
@@ -0,0 +1,717 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"bitbucket.org/gotamer/cases"
+	"github.com/jfcote87/intacct"
+)
+
+// lookupTypes maps lookup data types to the inspect data names of
+// dataTypeMap.  Other types are treated as strings.
+var lookupTypes = map[string]string{
+	"INTEGER":   "Pt_FieldInt",
+	"CURRENCY":  "Pt_FieldCurrency",
+	"DECIMAL":   "Pt_FieldDouble",
+	"NUMBER":    "Pt_FieldDouble",
+	"PERCENT":   "Pt_FieldDouble",
+	"BOOLEAN":   "Pt_FieldBoolean",
+	"DATE":      "Pt_FieldDate",
+	"TIMESTAMP": "Pt_FieldDateTime",
+}
+
+// lookupFields converts lookup fields to inspect form returning the
+// standard and custom fields separately.  Lookup does not return maximum
+// lengths, so they are taken from detail when available.
+func lookupFields(ot *intacct.ObjectType, detail *intacct.InspectDetailResult) (std, custom []intacct.FieldDetail) {
+	maxLens := make(map[string]string)
+	if detail != nil {
+		for _, fd := range detail.Fields {
+			maxLens[strings.ToUpper(fd.Name)] = fd.MaxLen
+		}
+	}
+	for _, f := range ot.Fields {
+		dataName, ok := lookupTypes[strings.ToUpper(f.DataType)]
+		if !ok {
+			dataName = "Pt_FieldString"
+		}
+		fd := intacct.FieldDetail{
+			Name:         f.ID,
+			DataName:     dataName,
+			IsRequired:   f.Required,
+			IsReadOnly:   f.ReadOnly,
+			DisplayLabel: f.Label,
+			Description:  f.Description,
+			MaxLen:       maxLens[strings.ToUpper(f.ID)],
+		}
+		if f.IsCustom {
+			custom = append(custom, fd)
+		} else {
+			std = append(std, fd)
+		}
+	}
+	return std, custom
+}
+
+// writeStruct writes the struct definition of an object followed by the
+// definitions of its nested structs.  seen holds the bodies of nested
+// structs already written to the package, possibly in another file, so
+// that identical types are written once and differing types with the
+// same name are renamed.
+//
+// With -lookup, the struct is generated from the lookup definition.  Custom
+// fields are placed in an embedded NAMECustom struct and relationships are
+// listed in a NAMERelationships var.
+//
+// With -writable, a NAMEWritable struct without read only fields is added
+// for create and update payloads.  With -validate, NAME (and NAMEWritable)
+// have a Validate method checking required fields and maximum lengths.
+func writeStruct(w io.Writer, def *definition, seen map[string]string) error {
+	var fields, custom []intacct.FieldDetail
+	var rels []intacct.ObjectRelationship
+	switch {
+	case *lookupFlag && def.Lookup == nil:
+		return fmt.Errorf("%s: no lookup definition", def.Name)
+	case *lookupFlag:
+		fields, custom = lookupFields(def.Lookup, def.Inspect)
+		rels = def.Lookup.Relationships
+	case def.Inspect == nil:
+		return fmt.Errorf("%s: no inspect definition", def.Name)
+	default:
+		fields = def.Inspect.Fields
+	}
+	baseName := makeTypeName(def.Name)
+	seen[baseName], seen[baseName+"Custom"] = "struct", "struct"
+	prevNames := make(map[string]string)
+	fldList, err := newFieldOutput(def, baseName, fields, prevNames)
+	if err != nil {
+		return err
+	}
+	customList, err := newFieldOutput(def, baseName, custom, prevNames)
+	if err != nil {
+		return err
+	}
+	var nested bytes.Buffer
+	fldList.writeStructs(&nested, baseName, seen)
+	customList.writeStructs(&nested, baseName, seen)
+	if err = fldList.writeEnums(&nested, def.Name, seen); err != nil {
+		return err
+	}
+	if err = customList.writeEnums(&nested, def.Name, seen); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "// %s (%s)\n", baseName, def.Name)
+	fmt.Fprintf(w, "type %s struct {\n", baseName)
+	fldList.writeFields(w)
+	if len(custom) > 0 {
+		fmt.Fprintf(w, "%sCustom\n", baseName)
+	}
+	writeCustomFields(w)
+	fmt.Fprintf(w, "}\n\n")
+	if *validateFlag {
+		writeValidate(w, baseName, def.Name, fldList, customList)
+	}
+	if len(custom) > 0 {
+		fmt.Fprintf(w, "// %sCustom contains the custom fields of %s\n", baseName, def.Name)
+		fmt.Fprintf(w, "type %sCustom struct {\n", baseName)
+		customList.writeFields(w)
+		fmt.Fprintf(w, "}\n\n")
+	}
+	if *writableFlag {
+		writeWritable(w, &nested, baseName, def.Name, fldList, customList, seen)
+	}
+	if *fieldsFlag {
+		writeFieldConsts(w, baseName, def.Name, append(fields, custom...))
+	}
+	if len(rels) > 0 {
+		fmt.Fprintf(w, "// %sRelationships lists the related objects of %s\n", baseName, def.Name)
+		fmt.Fprintf(w, "var %sRelationships = []intacct.ObjectRelationship{\n", baseName)
+		for _, r := range rels {
+			fmt.Fprintf(w, "{Path: %q, Name: %q, Lable: %q, Type: %q, RelatedBy: %q},\n", r.Path, r.Name, r.Lable, r.Type, r.RelatedBy)
+		}
+		fmt.Fprintf(w, "}\n\n")
+	}
+	_, err = nested.WriteTo(w)
+	return err
+}
+
+// newFieldOutput processes the fields of a struct
+func newFieldOutput(def *definition, baseName string, fields []intacct.FieldDetail, prevNames map[string]string) (*fieldOutput, error) {
+	fldList := &fieldOutput{List: make([]structureFields, 0, len(fields)), MultiField: make(map[string]*fieldOutput), PrevNames: prevNames, HandleMulti: !*queryFlag,
+		BoolTypes: boolTypes(def.Lookup), ValidValues: validValues(def.Lookup), TypePrefix: baseName}
+	for idx, f := range fields {
+		if err := fldList.process(f.Name, f, idx); err != nil {
+			return nil, fmt.Errorf("%s: unable to add field# %d (%s): %v", def.Name, idx, f.Name, err)
+		}
+	}
+	fldList.handleStructs()
+	return fldList, nil
+}
+
+// makeTypeName returns the object name as a valid type name
+func makeTypeName(objName string) string {
+	return strings.ToUpper(nr.Replace(objName))
+}
+
+// boolTypes maps field ids to intacct bool types using the valid values
+// of each field in ot when -bool-formats is set.
+func boolTypes(ot *intacct.ObjectType) map[string]string {
+	m := make(map[string]string)
+	if ot == nil || !*boolFlag {
+		return m
+	}
+	for _, f := range ot.Fields {
+		if ty := boolType(f.ValidValues); ty > "" {
+			m[strings.ToUpper(f.ID)] = ty
+		}
+	}
+	return m
+}
+
+// validValues maps field ids to valid values when -enums is set
+func validValues(ot *intacct.ObjectType) map[string][]string {
+	m := make(map[string][]string)
+	if ot == nil || !*enumFlag {
+		return m
+	}
+	for _, f := range ot.Fields {
+		if vals := uniqueStrings(f.ValidValues); len(vals) > 0 {
+			m[strings.ToUpper(f.ID)] = vals
+		}
+	}
+	return m
+}
+
+// boolType returns the bool type for a field whose valid values are a
+// T/F, Yes/No or active/inactive pair.
+func boolType(vals []string) string {
+	if len(vals) != 2 {
+		return ""
+	}
+	v0, v1 := strings.ToLower(vals[0]), strings.ToLower(vals[1])
+	if v0 > v1 {
+		v0, v1 = v1, v0
+	}
+	switch v0 + "/" + v1 {
+	case "f/t":
+		return "intacct.BoolTF"
+	case "no/yes":
+		return "intacct.BoolYesNo"
+	case "active/inactive":
+		return "intacct.BoolActive"
+	}
+	return ""
+}
+
+type structureFields struct {
+	OriIdx     int
+	Idx        int
+	ParentName string
+	FldName    string
+	DataType   string
+	XMLNm      string
+	IsReadOnly bool
+	IsRequired bool
+	IsText     bool // string or enum type
+	MaxLen     int
+	Comment    string
+	TopComment string
+	Doc        string // label and description written with -doc
+}
+
+type fieldOutput struct {
+	List        []structureFields
+	MultiField  map[string]*fieldOutput
+	PrevNames   map[string]string
+	HandleMulti bool
+	BoolTypes   map[string]string   // field id to bool type (see -bool-formats)
+	ValidValues map[string][]string // field id to valid values (see -enums)
+	TypePrefix  string              // object type name used to name enums
+	Enums       []enumType
+}
+
+// enumType describes a string type generated for a field with valid values
+type enumType struct {
+	Name   string
+	Field  string
+	XMLNm  string
+	Values []string
+}
+
+func (fo *fieldOutput) getFieldLabels(xnm string, f intacct.FieldDetail) (string, string, string, string, error) {
+	ty, ok := dataTypeMap[f.DataName]
+	if !ok {
+		ty = "interface{}"
+	}
+	if bt, ok := fo.BoolTypes[strings.ToUpper(xnm)]; ok {
+		ty = bt
+	}
+	var fdlbl = f.DisplayLabel
+	if fdlbl == "" {
+		fdlbl = strings.ToLower(f.Name)
+	}
+	if len(fdlbl) == 0 {
+		return "", "", "", "", fmt.Errorf("Empty Name")
+	}
+	if int(fdlbl[0]) >= 48 && int(fdlbl[0]) <= 57 {
+		fdlbl = "F" + fdlbl
+	}
+	fldSlice := strings.Split(fdlbl, ".")
+	var splitchar = "."
+	if len(fldSlice) == 0 {
+		fldSlice = strings.Split(fdlbl, "-")
+		splitchar = "-"
+	}
+	if len(fldSlice) > 0 {
+		fdlbl = strings.Replace(fdlbl, splitchar, "_", -1)
+	}
+
+	snm := cases.Camel(nr.Replace(fdlbl))
+
+	if _, ok = fo.PrevNames[snm]; ok {
+
+		snm = makeName(xnm)
+
+	} else {
+		fo.PrevNames[snm] = ""
+	}
+	if vals := fo.ValidValues[strings.ToUpper(xnm)]; ty == "string" && len(vals) > 0 {
+		ty = fo.TypePrefix + snm
+		fo.Enums = append(fo.Enums, enumType{Name: ty, Field: f.Name, XMLNm: xnm, Values: vals})
+	}
+	//}
+	comment := ""
+	if f.IsReadOnly {
+		comment = "// Read Only"
+	}
+	if f.IsRequired {
+		if comment > "" {
+			comment = comment + " Required"
+		} else {
+			comment = "// Required"
+		}
+
+	}
+	topComment := ""
+	if f.RelatedObject > "" {
+		topComment = fmt.Sprintf("// %s: %s\n", f.RelatedObject, f.Relationship)
+	}
+	return snm, ty, comment, topComment, nil
+}
+
+func makeName(fldNm string) string {
+	if int(fldNm[0]) >= 48 && int(fldNm[0]) <= 57 {
+		fldNm = "F" + fldNm
+	}
+	return cases.Camel(nr.Replace(fldNm))
+
+}
+
+func (fo *fieldOutput) process(nm string, fd intacct.FieldDetail, idx int) error {
+	sx := []string{nm}
+	if fo.HandleMulti {
+		sx = strings.Split(nm, ".")
+	}
+	sf := &structureFields{}
+	sf.Idx = len(fo.List)
+	sf.OriIdx = idx
+	if fd.RelatedObject > "" {
+		sf.Comment = fmt.Sprintf("// %s: %s", fd.RelatedObject, fd.Relationship)
+	}
+	if len(sx) == 1 {
+		enumCnt := len(fo.Enums)
+		fldNm, dataType, comment, topComment, err := fo.getFieldLabels(nm, fd)
+		if err != nil {
+			return err
+		}
+
+		sf.DataType = dataType
+		sf.IsText = dataType == "string" || len(fo.Enums) > enumCnt
+		sf.IsReadOnly = fd.IsReadOnly
+		sf.IsRequired = fd.IsRequired
+		sf.MaxLen, _ = strconv.Atoi(fd.MaxLen)
+		sf.Doc = fieldDoc(fd)
+		sf.FldName = fldNm
+		sf.Comment = comment
+		sf.TopComment = topComment
+		if fd.DataName == "Pt_FieldRelationship" {
+			nm = strings.ToUpper(nm)
+		}
+		sf.XMLNm = nm
+		fo.List = append(fo.List, *sf)
+
+	} else {
+		nextFo, ok := fo.MultiField[sx[0]]
+		if !ok {
+			//sf := &structureFields{}
+			sf.DataType = "struct{}"
+			sf.FldName = makeName(sx[0])
+			sf.XMLNm = sx[0]
+			fo.List = append(fo.List, *sf)
+			nextFo = &fieldOutput{List: make([]structureFields, 0), MultiField: make(map[string]*fieldOutput), PrevNames: make(map[string]string)}
+			fo.MultiField[sx[0]] = nextFo
+		}
+		return nextFo.process(strings.Join(sx[1:], "."), fd, idx)
+	}
+	return nil
+}
+
+// writeFields writes the field lines of a struct
+func (fo *fieldOutput) writeFields(w io.Writer) {
+	for _, f := range fo.List {
+		if f.TopComment > "" {
+			fmt.Fprint(w, f.TopComment)
+		}
+		if *docFlag && f.Doc > "" {
+			fmt.Fprintf(w, "// %s\n", f.Doc)
+		}
+		fmt.Fprintf(w, "%s %s `%s`%s\n", f.FldName, f.DataType, fieldTag(f.XMLNm), f.Comment)
+	}
+}
+
+// fieldTag returns the struct tag of a field adding a json tag with the
+// same name when -json is set.
+func fieldTag(xmlNm string) string {
+	if *jsonFlag {
+		return fmt.Sprintf("xml:\"%s,omitempty\" json:\"%s,omitempty\"", xmlNm, xmlNm)
+	}
+	return fmt.Sprintf("xml:\"%s,omitempty\"", xmlNm)
+}
+
+// writeCustomFields writes the CustomFields line of an object struct
+func writeCustomFields(w io.Writer) {
+	if *jsonFlag {
+		fmt.Fprint(w, "CustomFields intacct.CustomFields `xml:\",any\" json:\",omitempty\"`\n")
+		return
+	}
+	fmt.Fprint(w, "CustomFields intacct.CustomFields `xml:\",any\"`\n")
+}
+
+// fieldDoc returns the label and description of a field followed by the
+// maximum length of text fields.
+func fieldDoc(fd intacct.FieldDetail) string {
+	doc := strings.TrimSpace(fd.DisplayLabel)
+	if desc := strings.TrimSpace(fd.Description); desc > "" && !strings.EqualFold(desc, doc) {
+		if doc > "" {
+			doc += ": "
+		}
+		doc += desc
+	}
+	switch fd.DataName {
+	case "Pt_FieldString", "Pt_FieldText", "Pt_FieldRelationship":
+		if n, _ := strconv.Atoi(fd.MaxLen); n > 0 {
+			doc = strings.TrimSpace(fmt.Sprintf("%s (max length %d)", doc, n))
+		}
+	}
+	return strings.Join(strings.Fields(doc), " ")
+}
+
+// writable returns the fields of fo that are not read only and the
+// values assigning each from recv.  Nested structs containing read only
+// fields are replaced by writable versions written to w, and contacts are
+// copied with intacct.Contact.Writable.  changed is false when fo has no
+// read only fields at any level.
+func (fo *fieldOutput) writable(w io.Writer, recv string, seen map[string]string) (wo *fieldOutput, values []string, changed bool) {
+	wo = &fieldOutput{}
+	*wo = *fo
+	wo.List = nil
+	for _, f := range fo.List {
+		if f.IsReadOnly {
+			changed = true
+			continue
+		}
+		val := recv + f.FldName
+		if sub, ok := fo.MultiField[f.XMLNm]; ok && strings.HasPrefix(f.DataType, "*") {
+			if swo, svals, schanged := sub.writable(w, "v.", seen); schanged {
+				name := f.DataType[1:] + "Writable"
+				if _, ok := seen[name]; !ok {
+					seen[name] = "writable"
+					fmt.Fprintf(w, "// %s contains the fields of %s that may be sent by Create and Update\n", name, f.DataType[1:])
+					fmt.Fprintf(w, "type %s struct {\n", name)
+					swo.writeFields(w)
+					fmt.Fprintf(w, "}\n\n")
+					fmt.Fprintf(w, "// Writable returns v without read only fields\n")
+					fmt.Fprintf(w, "func (v %s) Writable() *%s {\nif v == nil {\nreturn nil\n}\nreturn &%s{\n%s,\n}\n}\n\n",
+						f.DataType, name, name, strings.Join(svals, ",\n"))
+				}
+				f.DataType, val, changed = "*"+name, val+".Writable()", true
+			}
+		} else if f.DataType == "*intacct.Contact" {
+			val, changed = val+".Writable()", true
+		}
+		wo.List = append(wo.List, f)
+		values = append(values, f.FldName+": "+val)
+	}
+	return wo, values, changed
+}
+
+// writeWritable writes NAMEWritable, the object struct without read only
+// fields, and a Writable method converting the object.  Like other object
+// structs it has no XMLName, so the object name is set by intacct.Create
+// and intacct.Update.  Writable versions of nested and custom structs are
+// written to nested.
+func writeWritable(w, nested io.Writer, baseName, objName string, fldList, customList *fieldOutput, seen map[string]string) {
+	wo, values, _ := fldList.writable(nested, "v.", seen)
+	typeName := baseName + "Writable"
+	customName := baseName + "Custom"
+	hasCustom := len(customList.List) > 0
+	if hasCustom {
+		cwo, cvals, changed := customList.writable(nested, "v."+customName+".", seen)
+		if changed {
+			fmt.Fprintf(nested, "// %sWritable contains the custom fields of %s that may be sent by Create and Update\n", customName, objName)
+			fmt.Fprintf(nested, "type %sWritable struct {\n", customName)
+			cwo.writeFields(nested)
+			fmt.Fprintf(nested, "}\n\n")
+			values = append(values, fmt.Sprintf("%sWritable: %sWritable{\n%s,\n}", customName, customName, strings.Join(cvals, ",\n")))
+			customName += "Writable"
+		} else {
+			values = append(values, fmt.Sprintf("%s: v.%s", customName, customName))
+		}
+		customList = cwo
+	}
+	fmt.Fprintf(w, "// %s contains the fields of %s that may be sent by Create and Update\n", typeName, objName)
+	fmt.Fprintf(w, "type %s struct {\n", typeName)
+	wo.writeFields(w)
+	if hasCustom {
+		fmt.Fprintf(w, "%s\n", customName)
+	}
+	writeCustomFields(w)
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "// Writable returns v without read only fields\n")
+	fmt.Fprintf(w, "func (v *%s) Writable() *%s {\nreturn &%s{\n", baseName, typeName, typeName)
+	for _, val := range values {
+		fmt.Fprintf(w, "%s,\n", val)
+	}
+	fmt.Fprintf(w, "CustomFields: v.CustomFields,\n}\n}\n\n")
+	if *validateFlag {
+		writeValidate(w, typeName, objName, wo, customList)
+	}
+}
+
+// writeValidate writes a Validate method returning intacct.QueryErrors for
+// blank required fields and text longer than its maximum length.  Read only
+// fields are not checked as they are never sent.  Required fields whose
+// type cannot be blank (e.g. intacct.Int) are noted with a comment.
+func writeValidate(w io.Writer, typeName, objName string, lists ...*fieldOutput) {
+	fmt.Fprintf(w, "// Validate checks required fields and maximum lengths before a create or update\n")
+	fmt.Fprintf(w, "func (v *%s) Validate() error {\nvar errs intacct.QueryErrors\n", typeName)
+	for _, fo := range lists {
+		for _, f := range fo.List {
+			if f.IsReadOnly {
+				continue
+			}
+			fldName := f.XMLNm
+			if f.IsRequired {
+				var blank string
+				switch {
+				case f.IsText:
+					blank = fmt.Sprintf("v.%s == \"\"", f.FldName)
+				case f.DataType == "intacct.Date" || f.DataType == "intacct.Datetime" || f.DataType == "intacct.Decimal":
+					blank = fmt.Sprintf("v.%s.IsNil()", f.FldName)
+				case strings.HasPrefix(f.DataType, "intacct.Null"):
+					blank = fmt.Sprintf("!v.%s.Valid", f.FldName)
+				case strings.HasPrefix(f.DataType, "*"):
+					blank = fmt.Sprintf("v.%s == nil", f.FldName)
+				}
+				if blank > "" {
+					fmt.Fprintf(w, "if %s {\nerrs = append(errs, intacct.QueryError{Element: %q, Field: %q, Message: \"required\"})\n}\n", blank, objName, fldName)
+				} else {
+					fmt.Fprintf(w, "// %s is required but not checked as a %s zero value may be set (see -nullable)\n", fldName, f.DataType)
+				}
+			}
+			if f.IsText && f.MaxLen > 0 {
+				fmt.Fprintf(w, "if len([]rune(string(v.%s))) > %d {\nerrs = append(errs, intacct.QueryError{Element: %q, Field: %q, Message: \"exceeds max length %d\"})\n}\n",
+					f.FldName, f.MaxLen, objName, fldName, f.MaxLen)
+			}
+		}
+	}
+	fmt.Fprintf(w, "if len(errs) > 0 {\nreturn errs\n}\nreturn nil\n}\n\n")
+}
+
+// writeStructs writes nested structs in name order, children first, and
+// sets the data type of the fields referencing them.  A name already used
+// by a different definition is prefixed with objName.
+func (fo *fieldOutput) writeStructs(w io.Writer, objName string, seen map[string]string) {
+	keys := make([]string, 0, len(fo.MultiField))
+	for k := range fo.MultiField {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := fo.MultiField[k]
+		v.writeStructs(w, objName, seen)
+		var buf bytes.Buffer
+		v.writeFields(&buf)
+		body := buf.String()
+		strName := string(k[0]) + strings.ToLower(k[1:])
+		if prev, ok := seen[strName]; ok && prev != body {
+			strName = makeName(objName) + strName
+		}
+		fo.setDataType(k, "*"+strName)
+		if _, ok := seen[strName]; ok {
+			continue
+		}
+		seen[strName] = body
+		fmt.Fprintf(w, "type %s struct {\n%s}\n\n", strName, body)
+	}
+}
+
+func (fo *fieldOutput) setDataType(xmlNm, dataType string) {
+	for idx := range fo.List {
+		if fo.List[idx].XMLNm == xmlNm {
+			fo.List[idx].DataType = dataType
+		}
+	}
+}
+
+// handleStructs replaces nested contact structs with intacct.Contact
+func (fo *fieldOutput) handleStructs() {
+	for idx, fx := range fo.List {
+		if fx.DataType == "struct{}" {
+			flx, ok := fo.MultiField[fx.XMLNm]
+			if ok {
+				tMap := make(map[string]bool)
+				for _, flds := range flx.List {
+					tMap[flds.XMLNm] = true
+				}
+				if tMap["PRINTAS"] && tMap["PHONE1"] && tMap["CONTACTNAME"] && tMap["MAILADDRESS.ADDRESS1"] {
+					fo.List[idx].DataType = "*intacct.Contact"
+					delete(fo.MultiField, fx.XMLNm)
+				}
+			}
+
+		}
+	}
+	for _, v := range fo.MultiField {
+		v.handleStructs()
+	}
+}
+
+// writeEnums writes the enum types of fo.  An enum whose name is already
+// declared in the file with a different definition is named by adding
+// Enum so that names depend only on the object and field.
+func (fo *fieldOutput) writeEnums(w io.Writer, objName string, seen map[string]string) error {
+	for _, e := range fo.Enums {
+		vals := e.Values
+		body := "enum " + strings.Join(vals, "\x00")
+		name := e.Name
+		if seen[name] > "" && seen[name] != body {
+			name += "Enum"
+			if seen[name] > "" && seen[name] != body {
+				return fmt.Errorf("%s.%s: enum type names %s and %s are already declared", objName, e.Field, e.Name, name)
+			}
+			fo.setDataType(e.XMLNm, name)
+		}
+		if seen[name] == body {
+			continue
+		}
+		seen[name] = body
+		writeEnum(w, name, objName+"."+e.Field, vals)
+	}
+	return nil
+}
+
+func writeEnum(w io.Writer, name, field string, vals []string) {
+	used := make(map[string]bool)
+	consts := make([]string, len(vals))
+	for i, v := range vals {
+		id := identifier(cases.Camel(v))
+		if id == "" {
+			id = "Value"
+		}
+		c := name + id
+		for n := 2; used[c]; n++ {
+			c = fmt.Sprintf("%s%s%d", name, id, n)
+		}
+		used[c] = true
+		consts[i] = c
+	}
+	fmt.Fprintf(w, "// %s is a valid value of %s\n", name, field)
+	fmt.Fprintf(w, "type %s string\n\n", name)
+	fmt.Fprintf(w, "// %s values\nconst (\n", name)
+	for i, v := range vals {
+		fmt.Fprintf(w, "%s %s = %q\n", consts[i], name, v)
+	}
+	fmt.Fprintf(w, ")\n\n")
+	fmt.Fprintf(w, "// Valid returns true for blank or a valid value.  A Service with StrictEnum\n// set uses Valid to check requests and decoded results.\n")
+	fmt.Fprintf(w, "func (v %s) Valid() bool {\nswitch v {\ncase \"\", %s:\nreturn true\n}\nreturn false\n}\n\n", name, strings.Join(consts, ", "))
+}
+
+// identifier removes characters not allowed in a Go identifier
+func identifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// uniqueStrings removes blank and repeated values keeping order
+func uniqueStrings(list []string) []string {
+	var vals []string
+	found := make(map[string]bool)
+	for _, v := range list {
+		if v > "" && !found[v] {
+			found[v] = true
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+// writeFieldConsts writes the object name constant, a constant for each
+// field name (dotted names included) and a list of all fields for use in
+// Query.Select and Reader.Fields.
+func writeFieldConsts(w io.Writer, baseName, objName string, fields []intacct.FieldDetail) {
+	fmt.Fprintf(w, "// %sObject is the object name used with Create, Update, Delete and Query.Object\n", baseName)
+	fmt.Fprintf(w, "const %sObject = %q\n\n", baseName, objName)
+	if len(fields) == 0 {
+		return
+	}
+	used := make(map[string]bool)
+	names := make([]string, len(fields))
+	fmt.Fprintf(w, "// %s field names\nconst (\n", objName)
+	for i, f := range fields {
+		names[i] = fieldConstName(baseName, f, used)
+		fmt.Fprintf(w, "%s = %q\n", names[i], f.Name)
+	}
+	fmt.Fprintf(w, ")\n\n")
+	fmt.Fprintf(w, "// %sFields lists the fields of %s for Query.Select and Reader.Fields\n", baseName, objName)
+	fmt.Fprintf(w, "var %sFields = []string{\n%s,\n}\n\n", baseName, strings.Join(names, ",\n"))
+}
+
+// fieldConstName returns BASEField followed by the camel cased label of
+// the field.  The label of a dotted field is prefixed with its path.  A
+// name already used is replaced by one made from the field name and then
+// by adding a numeric suffix.
+func fieldConstName(baseName string, f intacct.FieldDetail, used map[string]bool) string {
+	label := f.DisplayLabel
+	if label == "" {
+		label = strings.ToLower(f.Name)
+	}
+	nm := identifier(cases.Camel(nr.Replace(label)))
+	if idx := strings.LastIndex(f.Name, "."); idx > 0 {
+		var prefix string
+		for _, seg := range strings.Split(f.Name[:idx], ".") {
+			prefix += identifier(makeName(strings.ToLower(seg)))
+		}
+		nm = prefix + nm
+	}
+	name := baseName + "Field" + nm
+	if used[name] || nm == "" {
+		name = baseName + "Field" + identifier(makeName(f.Name))
+	}
+	for i, base := 2, name; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	used[name] = true
+	return name
+}
@@ -5,20 +5,31 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"log"
 	"os"
 	"strings"
 
-	"bitbucket.org/gotamer/cases"
 	"github.com/jfcote87/intacct"
 )
 
 var configFile = flag.String("cfg", "", "file name of a json or xml file containing the service definition")
 var queryFlag = flag.Bool("inline-fields", false, "flatten objects for ReadByQuery format")
-
-const usageMsg = "usage: -cfg [SERVICE_DEF_FILE] [-inline-fields] [OBJECTNAME....]"
+var decimalFlag = flag.Bool("decimal", false, "use intacct.Decimal for double fields as well as currency fields")
+var nullableFlag = flag.Bool("nullable", false, "use intacct.NullInt, NullFloat64 and NullBool for int, double and boolean fields")
+var boolFlag = flag.Bool("bool-formats", false, "lookup valid values and use intacct.BoolTF, BoolYesNo and BoolActive for T/F, Yes/No and active/inactive fields")
+var outFlag = flag.String("out", "", "directory for one file per object or a .go file for a combined file; default writes to stdout")
+var packageFlag = flag.String("package", "", "package name of generated files; default is the name of the output directory")
+var inFlag = flag.String("in", "", "comma separated list of files or directories of saved inspect/lookup xml or json used instead of a service")
+var saveFlag = flag.String("save", "", "directory to save the inspect and lookup results of a live run")
+var lookupFlag = flag.Bool("lookup", false, "generate from lookup definitions, separating custom fields and listing relationships")
+var enumFlag = flag.Bool("enums", false, "generate a string type with constants for each text field with valid values")
+var fieldsFlag = flag.Bool("fields", false, "generate an object name constant, field name constants and a field list")
+var jsonFlag = flag.Bool("json", false, "add json tags using the xml field names")
+var docFlag = flag.Bool("doc", false, "add doc comments from field labels, descriptions and maximum lengths")
+var validateFlag = flag.Bool("validate", false, "generate a Validate method checking required fields and maximum lengths")
+var writableFlag = flag.Bool("writable", false, "generate a NAMEWritable struct without read only fields for Create and Update")
+
+const usageMsg = "usage: -cfg [SERVICE_DEF_FILE] | -in [FILE|DIR,...] [-save DIR] [-inline-fields] [-decimal] [-nullable] [-bool-formats] [-lookup] [-enums] [-fields] [-json] [-doc] [-validate] [-writable] [-out DIR|FILE.go] [-package NAME] [OBJECTNAME....]"
 
 var dataTypeMap = map[string]string{
 	"Pt_FieldDateTime":     "intacct.Datetime",
@@ -30,6 +41,7 @@ var dataTypeMap = map[string]string{
 	"Pt_FieldBoolean":      "intacct.Bool",
 	"Pt_FieldDate":         "intacct.Date",
 	"Pt_FieldDouble":       "intacct.Float64",
+	"Pt_FieldCurrency":     "intacct.Decimal",
 }
 
 var nr = strings.NewReplacer("_", "", "(", "", ")", "", "%", "", "-", "", "/", "", ".", "", "'", "", ",", "")
@@ -40,21 +52,35 @@ func main() {
 	var msg string
 
 	flag.Parse()
+	if *nullableFlag {
+		dataTypeMap["Pt_FieldInt"] = "intacct.NullInt"
+		dataTypeMap["Pt_FieldDouble"] = "intacct.NullFloat64"
+		dataTypeMap["Pt_FieldBoolean"] = "intacct.NullBool"
+	}
+	if *decimalFlag {
+		dataTypeMap["Pt_FieldDouble"] = "intacct.Decimal"
+	}
 
-	if *configFile == "" {
+	switch {
+	case *inFlag > "":
+		err = generate(loadDefinitions(strings.Split(*inFlag, ",")...))
+	case *configFile == "":
 		msg = usageMsg
-	} else if sv, err = getService(*configFile); err != nil {
-		msg = fmt.Sprintf("error parsing %s: %v", *configFile, err)
-
+	default:
+		if sv, err = getService(*configFile); err != nil {
+			msg = fmt.Sprintf("error parsing %s: %v", *configFile, err)
+		}
 	}
 	if msg > "" {
 		fmt.Fprintf(os.Stdout, "%s\n", msg)
 		os.Exit(1)
 	}
-	if flag.NArg() == 0 {
-		err = listObjects(sv)
-	} else {
-		err = writeStructs(sv, flag.Args()...)
+	if sv != nil {
+		if flag.NArg() == 0 {
+			err = listObjects(sv)
+		} else {
+			err = generate(fetchDefinitions(sv, *boolFlag || *lookupFlag || *enumFlag || *saveFlag > "", flag.Args()...))
+		}
 	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v", err)
@@ -84,54 +110,28 @@ func listObjects(sv *intacct.Service) error {
 	return nil
 }
 
-func writeStructs(sv *intacct.Service, objNames ...string) error {
-	var funcs []intacct.Function
-	var results []interface{}
-
-	for _, objName := range objNames {
-		funcs = append(funcs, &intacct.Inspector{
-			IsDetail: 1,
-			Object:   objName,
-		})
-		results = append(results, &intacct.InspectDetailResult{})
-	}
-	resp, err := sv.Exec(context.Background(), funcs...)
+// generate saves definitions when -save is set and writes the struct
+// definitions of the objects named in the arguments (all objects for -in
+// when no names are passed).
+func generate(defs []*definition, err error) error {
 	if err != nil {
-		return fmt.Errorf("exec error: %v", err)
-	}
-
-	if err = resp.Decode(results...); err != nil {
-		return fmt.Errorf("decode error: %v", err)
-	}
-	for _, ix := range results {
-		writeStruct(ix.(*intacct.InspectDetailResult))
+		return err
 	}
-	return nil
-}
-
-func writeStruct(result *intacct.InspectDetailResult) {
-	fldList := &fieldOutput{List: make([]structureFields, 0, len(result.Fields)), MultiField: make(map[string]*fieldOutput), PrevNames: make(map[string]string), HandleMulti: !*queryFlag}
-	for idx, f := range result.Fields {
-		if err := fldList.process(f.Name, f, idx); err != nil {
-			log.Printf("Unable to add field# %d (%s): %v", idx, f.Name, err)
-			return
+	if *saveFlag > "" {
+		if err = saveDefinitions(*saveFlag, defs); err != nil {
+			return err
 		}
 	}
-	fldList.handleStructs()
-	var baseName = result.Name
-	w := os.Stdout
-	fmt.Fprintf(w, "// %s (%s)\n", baseName, result.Name)
-	fmt.Fprintf(w, "type %s struct {\n", baseName)
-
-	for _, f := range fldList.List {
-		if f.TopComment > "" {
-			fmt.Fprintf(w, f.TopComment)
+	if defs, err = selectDefinitions(defs, flag.Args()...); err != nil {
+		return err
+	}
+	out := newOutput(*outFlag, *packageFlag)
+	for _, def := range defs {
+		if err = out.add(def); err != nil {
+			return err
 		}
-		fmt.Fprintf(w, "%s %s `xml:\"%s,omitempty\"`%s\n", f.FldName, f.DataType, f.XMLNm, f.Comment)
 	}
-	fmt.Fprint(w, "CustomFields []intacct.CustomField `xml:\",any\"`\n")
-	fmt.Fprintf(w, "}\n\n")
-	fldList.writeStructs(os.Stdout)
+	return out.write(os.Stdout)
 }
 
 func getService(fn string) (*intacct.Service, error) {
@@ -142,170 +142,3 @@ func getService(fn string) (*intacct.Service, error) {
 	return intacct.ServiceFromConfigJSON(bytes.NewReader(b))
 
 }
-
-type structureFields struct {
-	OriIdx     int
-	Idx        int
-	ParentName string
-	FldName    string
-	DataType   string
-	XMLNm      string
-	IsReadOnly bool
-	IsRequired bool
-	Comment    string
-	TopComment string
-}
-
-type fieldOutput struct {
-	List        []structureFields
-	MultiField  map[string]*fieldOutput
-	PrevNames   map[string]string
-	HandleMulti bool
-}
-
-func (fo *fieldOutput) getFieldLabels(xnm string, f intacct.FieldDetail) (string, string, string, string, error) {
-	ty, ok := dataTypeMap[f.DataName]
-	if !ok {
-		ty = "interface{}"
-	}
-	var fdlbl = f.DisplayLabel
-	if fdlbl == "" {
-		fdlbl = strings.ToLower(f.Name)
-	}
-	if len(fdlbl) == 0 {
-		return "", "", "", "", fmt.Errorf("Empty Name")
-	}
-	if int(fdlbl[0]) >= 48 && int(fdlbl[0]) <= 57 {
-		fdlbl = "F" + fdlbl
-	}
-	fldSlice := strings.Split(fdlbl, ".")
-	var splitchar = "."
-	if len(fldSlice) == 0 {
-		fldSlice = strings.Split(fdlbl, "-")
-		splitchar = "-"
-	}
-	if len(fldSlice) > 0 {
-		fdlbl = strings.Replace(fdlbl, splitchar, "_", -1)
-	}
-
-	snm := cases.Camel(nr.Replace(fdlbl))
-
-	if _, ok = fo.PrevNames[snm]; ok {
-
-		snm = makeName(xnm)
-
-	} else {
-		fo.PrevNames[snm] = ""
-	}
-	//}
-	comment := ""
-	if f.IsReadOnly {
-		comment = "// Read Only"
-	}
-	if f.IsRequired {
-		if comment > "" {
-			comment = comment + " Required"
-		} else {
-			comment = "// Required"
-		}
-
-	}
-	topComment := ""
-	if f.RelatedObject > "" {
-		topComment = fmt.Sprintf("// %s: %s\n", f.RelatedObject, f.Relationship)
-	}
-	return snm, ty, comment, topComment, nil
-}
-
-func makeName(fldNm string) string {
-	if int(fldNm[0]) >= 48 && int(fldNm[0]) <= 57 {
-		fldNm = "F" + fldNm
-	}
-	return cases.Camel(nr.Replace(fldNm))
-
-}
-
-func (fo *fieldOutput) process(nm string, fd intacct.FieldDetail, idx int) error {
-	sx := []string{nm}
-	if fo.HandleMulti {
-		sx = strings.Split(nm, ".")
-	}
-	sf := &structureFields{}
-	sf.Idx = len(fo.List)
-	sf.OriIdx = idx
-	if fd.RelatedObject > "" {
-		sf.Comment = fmt.Sprintf("// %s: %s\n", fd.RelatedObject, fd.Relationship)
-	}
-	if len(sx) == 1 {
-		fldNm, dataType, comment, topComment, err := fo.getFieldLabels(nm, fd)
-		if err != nil {
-			return err
-		}
-
-		sf.DataType = dataType
-		sf.FldName = fldNm
-		sf.Comment = comment
-		sf.TopComment = topComment
-		if fd.DataName == "Pt_FieldRelationship" {
-			nm = strings.ToUpper(nm)
-		}
-		sf.XMLNm = nm
-		fo.List = append(fo.List, *sf)
-
-	} else {
-		nextFo, ok := fo.MultiField[sx[0]]
-		if !ok {
-			//sf := &structureFields{}
-			sf.DataType = "struct{}"
-			sf.FldName = makeName(sx[0])
-			sf.XMLNm = sx[0]
-			fo.List = append(fo.List, *sf)
-			nextFo = &fieldOutput{List: make([]structureFields, 0), MultiField: make(map[string]*fieldOutput), PrevNames: make(map[string]string)}
-			fo.MultiField[sx[0]] = nextFo
-		}
-		return nextFo.process(strings.Join(sx[1:], "."), fd, idx)
-	}
-	return nil
-}
-
-func (fo *fieldOutput) writeStructs(w io.Writer) {
-	for k, v := range fo.MultiField {
-		v.writeStructs(w)
-		strName := string(k[0]) + strings.ToLower(k[1:])
-		fmt.Fprintf(w, "type %s struct {\n", strName)
-		for _, fld := range v.List {
-			if fld.Comment > "" {
-				fmt.Fprintf(w, "// %s\n", fld.TopComment)
-			}
-			if fld.IsReadOnly || fld.IsRequired {
-
-			}
-			fmt.Fprintf(w, "%s %s `xml:\"%s,omitempty\"`%s\n", fld.FldName, fld.DataType, fld.XMLNm, fld.Comment)
-		}
-		fmt.Fprintf(w, "}\n\n")
-	}
-}
-
-func (fo *fieldOutput) handleStructs() {
-	for idx, fx := range fo.List {
-		if fx.DataType == "struct{}" {
-			flx, ok := fo.MultiField[fx.XMLNm]
-			if ok {
-				tMap := make(map[string]bool)
-				for _, flds := range flx.List {
-					tMap[flds.XMLNm] = true
-				}
-				if tMap["PRINTAS"] && tMap["PHONE1"] && tMap["CONTACTNAME"] && tMap["MAILADDRESS.ADDRESS1"] {
-					fo.List[idx].DataType = "*intacct.Contact"
-					delete(fo.MultiField, fx.XMLNm)
-				} else {
-					fo.List[idx].DataType = "*" + string(fx.XMLNm[0]) + strings.ToLower(string(fx.XMLNm[1:]))
-				}
-			}
-
-		}
-	}
-	for _, v := range fo.MultiField {
-		v.handleStructs()
-	}
-}
@@ -0,0 +1,482 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+)
+
+const fixtureDir = "../testfiles/genobject"
+
+func TestLoadDefinitions(t *testing.T) {
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(defs) != 2 || defs[0].Name != "APTERM" || defs[1].Name != "VENDOR" {
+		t.Fatalf("expected APTERM and VENDOR definitions; got %d", len(defs))
+	}
+	if defs[0].Inspect == nil || defs[0].Lookup != nil || len(defs[0].Inspect.Fields) != 4 {
+		t.Errorf("expected APTERM inspect definition with 4 fields from json")
+	}
+	if defs[1].Inspect == nil || defs[1].Lookup == nil || len(defs[1].Lookup.Relationships) != 2 {
+		t.Errorf("expected VENDOR inspect and lookup definitions")
+	}
+	if _, err = selectDefinitions(defs, "vendor", "NOTFOUND"); err == nil {
+		t.Errorf("expected error selecting unknown object")
+	}
+
+	dir, err := ioutil.TempDir("", "genobject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = saveDefinitions(dir, defs); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	saved, err := loadDefinitions(dir)
+	if err != nil {
+		t.Fatalf("load of saved definitions failed: %v", err)
+	}
+	if len(saved) != 2 || saved[1].Lookup == nil || len(saved[1].Inspect.Fields) != len(defs[1].Inspect.Fields) {
+		t.Errorf("saved definitions do not match fixtures")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "genobject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	outDir := filepath.Join(dir, "objects")
+
+	generateFiles := func() {
+		out := newOutput(outDir, "")
+		for _, def := range defs {
+			if err := out.add(def); err != nil {
+				t.Fatalf("add %s failed: %v", def.Name, err)
+			}
+		}
+		if err := out.write(nil); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	generateFiles()
+	fn := filepath.Join(outDir, "vendor.go")
+	src, err := ioutil.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("read generated file: %v", err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), fn, src, 0); err != nil {
+		t.Fatalf("generated file does not parse: %v", err)
+	}
+	for _, want := range []string{
+		"// Code generated by genobject. DO NOT EDIT.",
+		"package objects",
+		`import "github.com/jfcote87/intacct"`,
+		"type VENDOR struct",
+		`intacct.Int`, `xml:"RECORDNO,omitempty"`,
+		`*intacct.Contact`, `xml:"DISPLAYCONTACT,omitempty"`,
+		`intacct.Decimal`, `xml:"TOTALDUE,omitempty"`,
+		"type Payto struct",
+		"intacct.CustomFields", "`xml:\",any\"`",
+	} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("generated source missing %s", want)
+		}
+	}
+
+	// regeneration leaves unchanged files untouched
+	old := time.Now().Add(-time.Hour)
+	if err = os.Chtimes(fn, old, old); err != nil {
+		t.Fatal(err)
+	}
+	generateFiles()
+	if fi, err := os.Stat(fn); err != nil || !fi.ModTime().Equal(old) {
+		t.Errorf("expected unchanged file not to be rewritten")
+	}
+
+	var buf bytes.Buffer
+	out := newOutput("", "")
+	if err = out.add(defs[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err = out.write(&buf); err != nil {
+		t.Fatalf("write to stdout failed: %v", err)
+	}
+	if s := buf.String(); !strings.HasPrefix(s, "// VENDOR (VENDOR)") || strings.Contains(s, "package") {
+		t.Errorf("expected declarations only; got %s", s)
+	}
+}
+
+func TestGenerateSharedTypes(t *testing.T) {
+	*enumFlag, *writableFlag, *validateFlag = true, true, true
+	defer func() { *enumFlag, *writableFlag, *validateFlag = false, false, false }()
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	// APBILL shares VENDOR's Payto struct while APPYMT's differs
+	var payto intacct.FieldDetail
+	for _, fd := range defs[1].Inspect.Fields {
+		if fd.Name == "PAYTO.CONTACTNAME" {
+			payto = fd
+		}
+	}
+	for _, nm := range []string{"APBILL", "APPYMT"} {
+		fields := []intacct.FieldDetail{
+			{Name: "RECORDNO", DataName: "Pt_FieldInt", IsReadOnly: true},
+			payto,
+		}
+		if nm == "APPYMT" {
+			fields = append(fields, intacct.FieldDetail{Name: "PAYTO.TAXID", DataName: "Pt_FieldString"})
+		}
+		defs = append(defs, &definition{Name: nm, Inspect: &intacct.InspectDetailResult{Name: nm, Fields: fields}})
+	}
+	dir, err := ioutil.TempDir("", "genobject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	outDir := filepath.Join(dir, "objects")
+	out := newOutput(outDir, "")
+	for _, def := range defs {
+		if err := out.add(def); err != nil {
+			t.Fatalf("add %s failed: %v", def.Name, err)
+		}
+	}
+	if err := out.write(nil); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err = typeCheckDir(outDir); err != nil {
+		t.Fatalf("generated package does not type check: %v", err)
+	}
+	for fn, want := range map[string]string{
+		"apbill.go": "*Payto ",
+		"appymt.go": "type APPYMTPayto struct",
+	} {
+		src, err := ioutil.ReadFile(filepath.Join(outDir, fn))
+		if err != nil {
+			t.Fatalf("read generated file: %v", err)
+		}
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("%s missing %s", fn, want)
+		}
+		if bytes.Contains(src, []byte("type Payto struct")) {
+			t.Errorf("%s redeclares Payto", fn)
+		}
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// typeCheckDir type checks the generated package in dir against the
+// intacct package source.
+func typeCheckDir(dir string) error {
+	fset := token.NewFileSet()
+	std := importer.ForCompiler(fset, "source", nil)
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	intacctPkg, err := checkPackage(fset, "..", "github.com/jfcote87/intacct", notTest, &types.Config{
+		Importer: std,
+		Error:    func(error) {}, // dependencies outside the standard library are not resolved
+	})
+	if err != nil {
+		return err
+	}
+	_, err = checkPackage(fset, dir, "objects", nil, &types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if path == intacctPkg.Path() {
+				return intacctPkg, nil
+			}
+			return std.Import(path)
+		}),
+	})
+	return err
+}
+
+func checkPackage(fset *token.FileSet, dir, path string, filter func(os.FileInfo) bool, conf *types.Config) (*types.Package, error) {
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, p := range pkgs {
+		for _, f := range p.Files {
+			files = append(files, f)
+		}
+	}
+	pkg, err := conf.Check(path, fset, files, nil)
+	if conf.Error != nil {
+		err = nil
+	}
+	return pkg, err
+}
+
+func TestOutputImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genobject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "gen", "objects.go")
+	out := newOutput(fn, "")
+	f := out.file("VENDOR")
+	f.body.WriteString("type VENDOR struct {\nXMLName xml.Name `xml:\"VENDOR\"`\nNAME intacct.String\n}\n\n")
+	f.body.WriteString("func (v VENDOR) String() string {\nfmt := v.NAME\nreturn fmt.String()\n}\n")
+	if err = out.write(nil); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	src, err := ioutil.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("read generated file: %v", err)
+	}
+	want := generatedHeader + "package gen\n\nimport (\n\t\"encoding/xml\"\n\n\t\"github.com/jfcote87/intacct\"\n)\n\ntype VENDOR struct {"
+	if !strings.HasPrefix(string(src), want) {
+		t.Errorf("expected %s; got %s", want, src)
+	}
+
+	if paths, err := importsOf("gen", []byte("type T struct{}\n")); err != nil || len(paths) != 0 {
+		t.Errorf("expected no imports; got %v %v", paths, err)
+	}
+	if _, err = importsOf("gen", []byte("type T struct{")); err == nil {
+		t.Errorf("expected error for invalid source")
+	}
+}
+
+func TestGenerateLookup(t *testing.T) {
+	*lookupFlag = true
+	defer func() { *lookupFlag = false }()
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	out := newOutput("", "")
+	if err = out.add(defs[0]); err == nil {
+		t.Errorf("expected error for APTERM without lookup definition")
+	}
+	out = newOutput("", "")
+	if err = out.add(defs[1]); err != nil {
+		t.Fatalf("add VENDOR failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = out.write(&buf); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	src := buf.String()
+	if _, err = parser.ParseFile(token.NewFileSet(), "vendor.go", "package objects\n"+src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	custom := strings.Index(src, "type VENDORCustom struct")
+	for _, want := range []string{
+		"\tVENDORCustom\n",
+		`intacct.Float64`, `xml:"CREDITLIMIT,omitempty"`,
+		`intacct.Datetime`, `xml:"WHENMODIFIED,omitempty"`,
+		"var VENDORRelationships = []intacct.ObjectRelationship{",
+		`{Path: "TERM", Name: "APTERM", Lable: "Term", Type: "MANY2ONE", RelatedBy: "TERMNAME"},`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %s", want)
+		}
+	}
+	if region := strings.Index(src, `xml:"REGION,omitempty"`); custom < 0 || region < custom {
+		t.Errorf("expected REGION in VENDORCustom struct; got %s", src)
+	}
+}
+
+func TestGenerateEnums(t *testing.T) {
+	*lookupFlag, *enumFlag = true, true
+	defer func() { *lookupFlag, *enumFlag = false, false }()
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	var buf bytes.Buffer
+	seen := map[string]string{"VENDORPaymentPriority": "struct"}
+	if err = writeStruct(&buf, defs[1], seen); err != nil {
+		t.Fatalf("writeStruct failed: %v", err)
+	}
+	src := buf.String()
+	if _, err = parser.ParseFile(token.NewFileSet(), "vendor.go", "package objects\n"+src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	for _, want := range []string{
+		"PaymentPriority VENDORPaymentPriorityEnum `",
+		"type VENDORPaymentPriorityEnum string",
+		`VENDORPaymentPriorityEnumUrgent VENDORPaymentPriorityEnum = "Urgent"`,
+		"Region VENDORRegion `",
+		"func (v VENDORRegion) Valid() bool {",
+		"type VENDORStatus string",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %s", want)
+		}
+	}
+
+	buf.Reset()
+	seen = map[string]string{"VENDORPaymentPriority": "struct", "VENDORPaymentPriorityEnum": "struct"}
+	if err = writeStruct(&buf, defs[1], seen); err == nil {
+		t.Errorf("expected error when both enum names are declared")
+	}
+
+	buf.Reset()
+	writeEnum(&buf, "XStatus", "X.STATUS", []string{"Active", "active", "#"})
+	for _, want := range []string{`XStatusActive XStatus = "Active"`, `XStatusActive2 XStatus = "active"`, `XStatusValue XStatus = "#"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("enum source missing %s; got %s", want, buf.String())
+		}
+	}
+}
+
+func TestGenerateFieldConsts(t *testing.T) {
+	*fieldsFlag = true
+	defer func() { *fieldsFlag = false }()
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = writeStruct(&buf, defs[1], make(map[string]string)); err != nil {
+		t.Fatalf("writeStruct failed: %v", err)
+	}
+	src := buf.String()
+	if _, err = parser.ParseFile(token.NewFileSet(), "vendor.go", "package objects\n"+src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	for _, want := range []string{
+		`const VENDORObject = "VENDOR"`,
+		`VENDORFieldRecordNumber = "RECORDNO"`,
+		`= "DISPLAYCONTACT.MAILADDRESS.ADDRESS1"`,
+		"var VENDORFields = []string{\nVENDORFieldRecordNumber,",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %s", want)
+		}
+	}
+
+	used := make(map[string]bool)
+	fd := intacct.FieldDetail{Name: "NAME", DisplayLabel: "Name"}
+	for _, want := range []string{"XFieldName", "XFieldNAME", "XFieldNAME2"} {
+		if nm := fieldConstName("X", fd, used); nm != want {
+			t.Errorf("expected %s; got %s", want, nm)
+		}
+	}
+}
+
+func TestGenerateValidate(t *testing.T) {
+	*jsonFlag, *docFlag, *validateFlag, *writableFlag = true, true, true, true
+	defer func() { *jsonFlag, *docFlag, *validateFlag, *writableFlag = false, false, false, false }()
+	defs, err := loadDefinitions(fixtureDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	vendor := defs[1].Inspect
+	vendor.Fields = append(vendor.Fields, intacct.FieldDetail{Name: "PAYTO.RECORDNO", DataName: "Pt_FieldInt", IsReadOnly: true, DisplayLabel: "Pay to key"})
+	for i, fd := range vendor.Fields {
+		if fd.Name == "ONHOLD" || fd.Name == "LASTPAID" {
+			vendor.Fields[i].IsRequired, vendor.Fields[i].IsReadOnly = true, false
+		}
+	}
+	var buf bytes.Buffer
+	if err = writeStruct(&buf, defs[1], make(map[string]string)); err != nil {
+		t.Fatalf("writeStruct failed: %v", err)
+	}
+	src := buf.String()
+	if _, err = parser.ParseFile(token.NewFileSet(), "vendor.go", "package objects\n"+src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	for _, want := range []string{
+		"`xml:\"VENDORID,omitempty\" json:\"VENDORID,omitempty\"`",
+		"// ONHOLD is required but not checked as a intacct.Bool zero value may be set (see -nullable)\n",
+		"if v.LastPaid.IsNil() {",
+		"DISPLAYCONTACT: v.DISPLAYCONTACT.Writable(),",
+		"PAYTO *PaytoWritable `",
+		"PAYTO: v.PAYTO.Writable(),",
+		"func (v *Payto) Writable() *PaytoWritable {\nif v == nil {",
+		"type PaytoWritable struct {",
+		"`xml:\",any\" json:\",omitempty\"`",
+		"// Vendor ID: Unique ID of the vendor (max length 20)\n",
+		"// Credit limit\n",
+		"func (v *VENDOR) Validate() error {",
+		`if v.VendorID == "" {`,
+		`intacct.QueryError{Element: "VENDOR", Field: "VENDORID", Message: "required"}`,
+		"if len([]rune(string(v.VendorName))) > 100 {",
+		"type VENDORWritable struct {",
+		"func (v *VENDOR) Writable() *VENDORWritable {",
+		"func (v *VENDORWritable) Validate() error {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %s", want)
+		}
+	}
+	for _, typeName := range []string{"VENDORWritable", "PaytoWritable"} {
+		writable := src[strings.Index(src, "type "+typeName+" struct"):]
+		writable = writable[:strings.Index(writable, "}")]
+		for _, ro := range []string{"RECORDNO", "TOTALDUE", "WHENMODIFIED"} {
+			if strings.Contains(writable, ro) {
+				t.Errorf("%s contains read only field %s", typeName, ro)
+			}
+		}
+	}
+	if strings.Contains(src, "RecordNumber: v.RecordNumber") {
+		t.Errorf("read only field copied to writable struct")
+	}
+
+	*lookupFlag = true
+	defer func() { *lookupFlag = false }()
+	buf.Reset()
+	if err = writeStruct(&buf, defs[1], make(map[string]string)); err != nil {
+		t.Fatalf("writeStruct with lookup failed: %v", err)
+	}
+	for _, want := range []string{
+		"if len([]rune(string(v.Region))) > 40 {",
+		"VENDORCustom: v.VENDORCustom,",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("lookup source missing %s", want)
+		}
+	}
+
+	lookup := defs[1].Lookup
+	lookup.Fields = append(lookup.Fields, intacct.ObjectField{ID: "REGIONKEY", Label: "Region key", DataType: "INTEGER", ReadOnly: true, IsCustom: true})
+	buf.Reset()
+	if err = writeStruct(&buf, defs[1], make(map[string]string)); err != nil {
+		t.Fatalf("writeStruct with read only custom field failed: %v", err)
+	}
+	src = buf.String()
+	if _, err = parser.ParseFile(token.NewFileSet(), "vendor.go", "package objects\n"+src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	for _, want := range []string{
+		"type VENDORCustomWritable struct {",
+		"\nVENDORCustomWritable\n",
+		"VENDORCustomWritable: VENDORCustomWritable{\nRegion: v.VENDORCustom.Region,\n},",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("lookup source missing %s", want)
+		}
+	}
+	writable := src[strings.Index(src, "type VENDORCustomWritable struct"):]
+	if writable = writable[:strings.Index(writable, "}")]; strings.Contains(writable, "REGIONKEY") {
+		t.Errorf("writable custom struct contains read only field REGIONKEY")
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const generatedHeader = "// Code generated by genobject. DO NOT EDIT.\n\n"
+
+// output collects generated declarations and writes them to stdout, a
+// directory (one file per object) or a single combined file.
+type output struct {
+	path     string // directory or .go file, empty for stdout
+	pkg      string
+	combined bool
+	files    []genFile
+	seen     map[string]string // declarations of the package shared by all files
+}
+
+type genFile struct {
+	name string
+	body bytes.Buffer
+}
+
+func newOutput(path, pkg string) *output {
+	o := &output{path: path, pkg: pkg, combined: path == "" || strings.HasSuffix(path, ".go"), seen: make(map[string]string)}
+	if o.pkg == "" && path != "" {
+		dir := path
+		if o.combined {
+			dir = filepath.Dir(path)
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		o.pkg = strings.Replace(strings.ToLower(filepath.Base(dir)), "-", "", -1)
+	}
+	return o
+}
+
+// file returns the file for an object, creating one if needed
+func (o *output) file(objName string) *genFile {
+	if o.combined && len(o.files) > 0 {
+		return &o.files[0]
+	}
+	name := o.path
+	if !o.combined {
+		name = filepath.Join(o.path, strings.ToLower(objName)+".go")
+	}
+	o.files = append(o.files, genFile{name: name})
+	return &o.files[len(o.files)-1]
+}
+
+// add generates the struct definitions of an object
+func (o *output) add(def *definition) error {
+	f := o.file(def.Name)
+	return writeStruct(&f.body, def, o.seen)
+}
+
+// write formats each file and writes it.  Files whose content is
+// unchanged are not rewritten so that regeneration only touches files
+// affected by schema changes.
+func (o *output) write(stdout io.Writer) error {
+	for _, f := range o.files {
+		src, err := o.source(f)
+		if err != nil {
+			return err
+		}
+		if o.path == "" {
+			if _, err = stdout.Write(src); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = writeIfChanged(f.name, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// source returns the gofmt'ed content of a file.  Output to stdout
+// contains declarations only.
+func (o *output) source(f genFile) ([]byte, error) {
+	var buf bytes.Buffer
+	if o.path != "" {
+		buf.WriteString(generatedHeader)
+		fmt.Fprintf(&buf, "package %s\n\n", o.pkg)
+		paths, err := importsOf(o.pkg, f.body.Bytes())
+		if err != nil {
+			return nil, invalidSource(f, err)
+		}
+		writeImports(&buf, paths)
+	}
+	buf.Write(f.body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, invalidSource(f, err)
+	}
+	return src, nil
+}
+
+func invalidSource(f genFile, err error) error {
+	name := f.name
+	if name == "" {
+		name = "stdout"
+	}
+	return fmt.Errorf("%s: generated invalid source: %v", name, err)
+}
+
+// knownImports lists the packages that generated code may reference by
+// package name.
+var knownImports = map[string]string{
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"intacct": "github.com/jfcote87/intacct",
+	"strings": "strings",
+	"time":    "time",
+	"xml":     "encoding/xml",
+}
+
+// importsOf returns the sorted import paths of the packages referenced
+// in body.  Names declared in body are not treated as package references.
+func importsOf(pkg string, body []byte) ([]string, error) {
+	src := append([]byte("package "+pkg+"\n\n"), body...)
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				if path, ok := knownImports[id.Name]; ok {
+					used[path] = true
+				}
+			}
+		}
+		return true
+	})
+	var paths []string
+	for path := range used {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// writeImports writes an import declaration with standard library
+// packages grouped ahead of others.
+func writeImports(buf *bytes.Buffer, paths []string) {
+	switch len(paths) {
+	case 0:
+		return
+	case 1:
+		fmt.Fprintf(buf, "import %q\n\n", paths[0])
+		return
+	}
+	var std, other []string
+	for _, path := range paths {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+			continue
+		}
+		std = append(std, path)
+	}
+	buf.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(buf, "%q\n", path)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(buf, "%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+}
+
+func writeIfChanged(name string, src []byte) error {
+	if cur, err := ioutil.ReadFile(name); err == nil && bytes.Equal(cur, src) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, src, 0644)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfcote87/intacct"
+)
+
+// definition holds the inspect and lookup descriptions of an object.
+// Either may be nil.
+type definition struct {
+	Name    string
+	Inspect *intacct.InspectDetailResult
+	Lookup  *intacct.ObjectType
+}
+
+// fetchDefinitions calls inspect for each object and, if withLookup is
+// set, lookup.
+func fetchDefinitions(sv *intacct.Service, withLookup bool, objNames ...string) ([]*definition, error) {
+	var funcs []intacct.Function
+	var results []interface{}
+	var defs []*definition
+	for _, objName := range objNames {
+		def := &definition{Name: strings.ToUpper(objName), Inspect: &intacct.InspectDetailResult{}}
+		funcs = append(funcs, &intacct.Inspector{
+			IsDetail: 1,
+			Object:   objName,
+		})
+		results = append(results, def.Inspect)
+		if withLookup {
+			def.Lookup = &intacct.ObjectType{}
+			funcs = append(funcs, intacct.Lookup{ObjectName: objName})
+			results = append(results, def.Lookup)
+		}
+		defs = append(defs, def)
+	}
+	resp, err := sv.Exec(context.Background(), funcs...)
+	if err != nil {
+		return nil, fmt.Errorf("exec error: %v", err)
+	}
+	if err = resp.Decode(results...); err != nil {
+		return nil, fmt.Errorf("decode error: %v", err)
+	}
+	for _, def := range defs {
+		if def.Inspect.Name > "" {
+			def.Name = def.Inspect.Name
+		}
+	}
+	return defs, nil
+}
+
+// loadDefinitions reads inspect and lookup results saved as xml or json.
+// Each path may be a file or a directory whose .xml and .json files are
+// read.  Definitions are returned sorted by name.
+func loadDefinitions(paths ...string) ([]*definition, error) {
+	var files []string
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		for _, ext := range []string{"*.xml", "*.json"} {
+			matches, _ := filepath.Glob(filepath.Join(p, ext))
+			files = append(files, matches...)
+		}
+	}
+	defMap := make(map[string]*definition)
+	for _, fn := range files {
+		inspect, lookup, err := readDefinitionFile(fn)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fn, err)
+		}
+		var name string
+		if inspect != nil {
+			name = inspect.Name
+		} else {
+			name = lookup.Name
+		}
+		if name == "" {
+			return nil, fmt.Errorf("%s: no object name", fn)
+		}
+		key := strings.ToUpper(name)
+		def, ok := defMap[key]
+		if !ok {
+			def = &definition{Name: name}
+			defMap[key] = def
+		}
+		if inspect != nil {
+			def.Inspect = inspect
+		} else {
+			def.Lookup = lookup
+		}
+	}
+	defs := make([]*definition, 0, len(defMap))
+	for _, def := range defMap {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Name < defs[j].Name
+	})
+	return defs, nil
+}
+
+// readDefinitionFile decodes an InspectDetailResult or ObjectType saved as
+// xml or json.  A lookup result is recognized by its fields' data types.
+func readDefinitionFile(fn string) (*intacct.InspectDetailResult, *intacct.ObjectType, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	b = bytes.TrimSpace(b)
+	unmarshal := xml.Unmarshal
+	if bytes.HasPrefix(b, []byte("{")) {
+		unmarshal = json.Unmarshal
+	}
+	var lookup *intacct.ObjectType
+	if err = unmarshal(b, &lookup); err != nil {
+		return nil, nil, err
+	}
+	if lookup == nil {
+		return nil, nil, errors.New("empty definition")
+	}
+	for _, f := range lookup.Fields {
+		if f.DataType > "" {
+			return nil, lookup, nil
+		}
+	}
+	var inspect *intacct.InspectDetailResult
+	if err = unmarshal(b, &inspect); err != nil {
+		return nil, nil, err
+	}
+	return inspect, nil, nil
+}
+
+// selectDefinitions returns the named definitions in the order of names.
+// All definitions are returned when no names are passed.
+func selectDefinitions(defs []*definition, names ...string) ([]*definition, error) {
+	if len(names) == 0 {
+		return defs, nil
+	}
+	var list []*definition
+	for _, nm := range names {
+		var found *definition
+		for _, def := range defs {
+			if strings.EqualFold(def.Name, nm) {
+				found = def
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no saved definition for %s", nm)
+		}
+		list = append(list, found)
+	}
+	return list, nil
+}
+
+// saveDefinitions writes each inspect and lookup result to dir as
+// NAME.inspect.xml and NAME.lookup.xml.
+func saveDefinitions(dir string, defs []*definition) error {
+	for _, def := range defs {
+		base := filepath.Join(dir, strings.ToLower(def.Name))
+		if def.Inspect != nil {
+			if err := saveXML(base+".inspect.xml", def.Inspect); err != nil {
+				return err
+			}
+		}
+		if def.Lookup != nil {
+			if err := saveXML(base+".lookup.xml", def.Lookup); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func saveXML(fn string, v interface{}) error {
+	b, err := xml.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	return writeIfChanged(fn, append(b, '\n'))
+}
@@ -8,4 +8,5 @@ require (
 	github.com/jfcote87/testutils v0.0.0-20190527035656-94af7a2b3405
 	golang.org/x/net v0.0.0-20190607181551-461777fb6f67 // indirect
 	google.golang.org/appengine v1.6.1 // indirect
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,371 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSyncOverlap is the amount of time each sync re-reads prior to the
+// saved watermark to protect against clock skew and in-flight transactions.
+const DefaultSyncOverlap = 5 * time.Minute
+
+// SyncMark records the progress of reading a stream of records ordered by
+// a timestamp field.  Seen holds the keys and timestamps of records
+// delivered within the overlap window and is used to remove duplicates.
+type SyncMark struct {
+	Watermark time.Time            `json:"watermark"`
+	Seen      map[string]time.Time `json:"seen,omitempty"`
+}
+
+func (sm *SyncMark) add(key string, tm time.Time) {
+	if sm.Seen == nil {
+		sm.Seen = make(map[string]time.Time)
+	}
+	sm.Seen[key] = tm
+	if tm.After(sm.Watermark) {
+		sm.Watermark = tm
+	}
+}
+
+// prune removes seen records older than the overlap window
+func (sm *SyncMark) prune(overlap time.Duration) {
+	start := sm.Watermark.Add(-overlap)
+	for k, tm := range sm.Seen {
+		if tm.Before(start) {
+			delete(sm.Seen, k)
+		}
+	}
+}
+
+func (sm *SyncMark) isSeen(key string, tm time.Time) bool {
+	prev, ok := sm.Seen[key]
+	return ok && prev.Equal(tm)
+}
+
+// SyncCheckpoint is the saved state of an object's incremental sync.
+type SyncCheckpoint struct {
+	Changes SyncMark `json:"changes"`
+	Deletes SyncMark `json:"deletes"`
+}
+
+// SyncStateStore persists sync checkpoints by object name.  Load
+// returns nil, nil when no checkpoint exists.
+type SyncStateStore interface {
+	Load(ctx context.Context, objectName string) (*SyncCheckpoint, error)
+	Save(ctx context.Context, objectName string, cp *SyncCheckpoint) error
+}
+
+// MemorySyncState is a SyncStateStore for testing and short lived processes
+type MemorySyncState struct {
+	m      sync.Mutex
+	states map[string][]byte
+}
+
+// Load returns a copy of the object's checkpoint
+func (ms *MemorySyncState) Load(ctx context.Context, objectName string) (*SyncCheckpoint, error) {
+	ms.m.Lock()
+	defer ms.m.Unlock()
+	b, ok := ms.states[objectName]
+	if !ok {
+		return nil, nil
+	}
+	var cp *SyncCheckpoint
+	return cp, json.Unmarshal(b, &cp)
+}
+
+// Save stores a copy of the object's checkpoint
+func (ms *MemorySyncState) Save(ctx context.Context, objectName string, cp *SyncCheckpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	ms.m.Lock()
+	defer ms.m.Unlock()
+	if ms.states == nil {
+		ms.states = make(map[string][]byte)
+	}
+	ms.states[objectName] = b
+	return nil
+}
+
+// FileSyncState stores checkpoints of all objects in a single json file
+type FileSyncState struct {
+	Path string
+	m    sync.Mutex
+}
+
+func (fs *FileSyncState) read() (map[string]*SyncCheckpoint, error) {
+	var states = make(map[string]*SyncCheckpoint)
+	b, err := ioutil.ReadFile(fs.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return states, nil
+		}
+		return nil, err
+	}
+	return states, json.Unmarshal(b, &states)
+}
+
+// Load reads the object's checkpoint from the file
+func (fs *FileSyncState) Load(ctx context.Context, objectName string) (*SyncCheckpoint, error) {
+	fs.m.Lock()
+	defer fs.m.Unlock()
+	states, err := fs.read()
+	if err != nil {
+		return nil, err
+	}
+	return states[objectName], nil
+}
+
+// Save updates the object's checkpoint in the file
+func (fs *FileSyncState) Save(ctx context.Context, objectName string, cp *SyncCheckpoint) error {
+	fs.m.Lock()
+	defer fs.m.Unlock()
+	states, err := fs.read()
+	if err != nil {
+		return err
+	}
+	states[objectName] = cp
+	b, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpName := fs.Path + ".tmp"
+	if err = ioutil.WriteFile(tmpName, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, fs.Path)
+}
+
+// Syncer reads records of an object created or modified since the previous
+// run.  Records are read in WHENMODIFIED order and passed to Sink in
+// batches.  The watermark is saved after each successful batch so that a
+// failed run resumes where it stopped.  Each run re-reads records modified
+// within Overlap of the watermark, removing records already delivered by
+// RECORDNO.
+//
+// When DeleteSink is set, deletions are read from the AUDITHISTORY object.
+type Syncer struct {
+	Service *Service
+	Object  string
+	// Fields to select.  RECORDNO and WHENMODIFIED are added if missing.
+	Fields []string
+	// Filter adds criteria to the query (optional)
+	Filter *Filter
+	State  SyncStateStore
+	// Start is the initial watermark when no checkpoint exists.  A zero
+	// value reads all records.
+	Start    time.Time
+	Overlap  time.Duration // DefaultSyncOverlap if zero
+	PageSize int           // 1000 if zero
+	// Sink receives each batch of new and changed records
+	Sink func(ctx context.Context, records []ResultMap) error
+	// DeleteSink receives the OBJECTKEY values of deleted records (optional)
+	DeleteSink func(ctx context.Context, keys []string) error
+	// AuditObjectType is the OBJECTTYPE value of the object in AUDITHISTORY.
+	// Defaults to the lower case object name.
+	AuditObjectType string
+}
+
+// Run reads all changes since the last checkpoint
+func (s *Syncer) Run(ctx context.Context) error {
+	if s.Service == nil || s.State == nil || s.Sink == nil || s.Object == "" {
+		return errors.New("syncer requires Service, State, Sink and Object")
+	}
+	cp, err := s.State.Load(ctx, s.Object)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = &SyncCheckpoint{
+			Changes: SyncMark{Watermark: s.Start},
+			Deletes: SyncMark{Watermark: s.Start},
+		}
+	}
+	save := func() error {
+		return s.State.Save(ctx, s.Object, cp)
+	}
+	changes := &syncStream{
+		object:    s.Object,
+		keyField:  "RECORDNO",
+		timeField: "WHENMODIFIED",
+		fields:    s.Fields,
+		filter:    s.Filter,
+		mark:      &cp.Changes,
+		loc:       s.Service.Location(),
+	}
+	if err = changes.run(ctx, s, s.Sink, save); err != nil || s.DeleteSink == nil {
+		return err
+	}
+
+	auditType := s.AuditObjectType
+	if auditType == "" {
+		auditType = strings.ToLower(s.Object)
+	}
+	var f *Filter
+	deletes := &syncStream{
+		object:    "AUDITHISTORY",
+		keyField:  "OBJECTKEY",
+		timeField: "ACCESSTIME",
+		filter:    f.EqualTo("OBJECTTYPE", auditType).EqualTo("WORKFLOWACTION", "delete"),
+		mark:      &cp.Deletes,
+		loc:       s.Service.Location(),
+	}
+	return deletes.run(ctx, s, func(ctx context.Context, recs []ResultMap) error {
+		keys := make([]string, 0, len(recs))
+		for _, rec := range recs {
+			keys = append(keys, rec.String("OBJECTKEY"))
+		}
+		return s.DeleteSink(ctx, keys)
+	}, save)
+}
+
+func (s *Syncer) overlap() time.Duration {
+	if s.Overlap <= 0 {
+		return DefaultSyncOverlap
+	}
+	return s.Overlap
+}
+
+func (s *Syncer) pageSize() int {
+	if s.PageSize <= 0 {
+		return 1000
+	}
+	return s.PageSize
+}
+
+// syncStream reads records of an object in timeField, keyField order
+type syncStream struct {
+	object    string
+	keyField  string
+	timeField string
+	fields    []string
+	filter    *Filter
+	mark      *SyncMark
+	loc       *time.Location // zone of timeField values
+}
+
+func (st *syncStream) query(from time.Time, offset, pageSize int) Query {
+	flds := append([]string{}, st.fields...)
+	for _, nm := range []string{st.keyField, st.timeField} {
+		if !containsFold(flds, nm) {
+			flds = append(flds, nm)
+		}
+	}
+	return Query{
+		Object: st.object,
+		Select: Select{Fields: flds},
+		Filter: st.makeFilter(from),
+		Sort: &QuerySort{Fields: []OrderBy{
+			{Field: st.timeField},
+			{Field: st.keyField},
+		}},
+		PageSz: pageSize,
+		Offset: offset,
+	}
+}
+
+// makeFilter combines the stream's filter with the watermark criteria
+func (st *syncStream) makeFilter(from time.Time) *Filter {
+	var conds []Filter
+	if st.filter != nil {
+		switch st.filter.XMLName.Local {
+		case "", "filter":
+			conds = append(conds, st.filter.Filters...)
+		default:
+			conds = append(conds, *st.filter)
+		}
+	}
+	if !from.IsZero() {
+		conds = append(conds, Filter{
+			XMLName: xml.Name{Local: "greaterthanorequalto"},
+			Field:   st.timeField,
+			Value:   FilterVals{FormatTime(from, st.loc)},
+		})
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	root := NewFilter()
+	if len(conds) == 1 {
+		root.Filters = conds
+	} else {
+		root.And().Filters = conds
+	}
+	root.setParents()
+	return root
+}
+
+// run reads pages using the last timestamp of each page as the start of
+// the next, skipping records at that timestamp already read.  This prevents
+// records modified during the run from shifting pages and being skipped.
+func (st *syncStream) run(ctx context.Context, s *Syncer, deliver func(context.Context, []ResultMap) error, save func() error) error {
+	overlap := s.overlap()
+	var from time.Time
+	if !st.mark.Watermark.IsZero() {
+		from = st.mark.Watermark.Add(-overlap).Truncate(time.Second)
+	}
+	var offset int
+	for {
+		resp, err := s.Service.Exec(ctx, st.query(from, offset, s.pageSize()))
+		if err != nil {
+			return err
+		}
+		var recs []ResultMap
+		if err = resp.Decode(&recs); err != nil {
+			return err
+		}
+		var batch []ResultMap
+		var times []time.Time
+		var pageMax time.Time
+		var atMax int
+		for _, rec := range recs {
+			var dt Datetime
+			if err = dt.UnmarshalText([]byte(rec.String(st.timeField))); err != nil || dt.IsNil() {
+				return fmt.Errorf("%s %s: invalid %s %q", st.object, rec.String(st.keyField), st.timeField, rec.String(st.timeField))
+			}
+			tm := *dt.In(st.loc)
+			if tm.After(pageMax) {
+				pageMax, atMax = tm, 0
+			}
+			atMax++
+			if st.mark.isSeen(rec.String(st.keyField), tm) {
+				continue
+			}
+			batch, times = append(batch, rec), append(times, tm)
+		}
+		if len(batch) > 0 {
+			if err = deliver(ctx, batch); err != nil {
+				return err
+			}
+			for i, rec := range batch {
+				st.mark.add(rec.String(st.keyField), times[i])
+			}
+			st.mark.prune(overlap)
+			if err = save(); err != nil {
+				return err
+			}
+		}
+		if len(recs) == 0 || len(resp.Results) == 0 || resp.Results[0].Data == nil || resp.Results[0].Data.NumRemaining == 0 {
+			return nil
+		}
+		if pageMax.Equal(from) {
+			offset += atMax
+		} else {
+			from, offset = pageMax, atMax
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+	"github.com/jfcote87/testutils"
+)
+
+func syncResponse(object string, remaining int, recs ...string) []byte {
+	var data string
+	for _, r := range recs {
+		flds := strings.Split(r, ",")
+		if object == "AUDITHISTORY" {
+			data += fmt.Sprintf("<AUDITHISTORY><OBJECTKEY>%s</OBJECTKEY><ACCESSTIME>%s</ACCESSTIME></AUDITHISTORY>", flds[0], flds[1])
+			continue
+		}
+		data += fmt.Sprintf("<%s><RECORDNO>%s</RECORDNO><WHENMODIFIED>%s</WHENMODIFIED></%s>", object, flds[0], flds[1], object)
+	}
+	return queryResponse(object, remaining, data, len(recs))
+}
+
+// queryResponse returns a query function response containing data
+func queryResponse(object string, remaining int, data string, count int) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<response>
+	<control><status>success</status><senderid>SENDERID</senderid><controlid>1</controlid><uniqueid>false</uniqueid><dtdversion>3.0</dtdversion></control>
+	<operation>
+		<authentication><status>success</status><userid>xml_gateway</userid><companyid>Company</companyid></authentication>
+		<result><status>success</status><function>query</function><controlid>1</controlid>
+		<data listtype="%s" count="%d" totalcount="%d" numremaining="%d">%s</data></result>
+	</operation>
+</response>`, object, count, count+remaining, remaining, data))
+}
+
+// syncTester checks that the request contains each of the expected strings
+func syncTester(body []byte, expect ...string) *testutils.RequestTester {
+	return &testutils.RequestTester{
+		ResponseFunc: func(r *http.Request) (*http.Response, error) {
+			defer r.Body.Close()
+			b, _ := ioutil.ReadAll(r.Body)
+			for _, s := range expect {
+				if !strings.Contains(string(b), s) {
+					return nil, fmt.Errorf("expected request to contain %s; got %s", s, b)
+				}
+			}
+			return testutils.MakeResponse(200, body, xmlHeader), nil
+		},
+	}
+}
+
+func TestSyncer(t *testing.T) {
+	ctx := context.Background()
+	testTransport := &testutils.Transport{}
+	testTransport.Add(
+		// first run: two pages of changes and one delete
+		syncTester(syncResponse("VENDOR", 1, "1,01/02/2021 10:00:00", "2,01/02/2021 10:01:00"),
+			"<object>VENDOR</object>", "<field>NAME</field><field>recordno</field><field>WHENMODIFIED</field></select>", "<pagesize>2</pagesize>"),
+		syncTester(syncResponse("VENDOR", 0, "3,01/02/2021 10:02:00"),
+			"<greaterthanorequalto><field>WHENMODIFIED</field><value>01/02/2021 10:01:00</value>", "<offset>1</offset>"),
+		syncTester(syncResponse("AUDITHISTORY", 0, "9,01/02/2021 10:01:30"),
+			"<object>AUDITHISTORY</object>", "<value>vendor</value>", "<value>delete</value>"),
+		// second run: record 3 is re-read within the overlap and skipped
+		syncTester(syncResponse("VENDOR", 0, "3,01/02/2021 10:02:00", "2,01/02/2021 10:03:00"),
+			"<value>01/02/2021 09:57:00</value>"),
+		syncTester(syncResponse("AUDITHISTORY", 0, "9,01/02/2021 10:01:30"),
+			"<value>01/02/2021 09:56:30</value>"),
+	)
+	sv := &intacct.Service{
+		SenderID:      "SENDERID",
+		Password:      "*******",
+		Authenticator: intacct.SessionID("SESSIONID"),
+		HTTPClientFunc: func(ctx context.Context) (*http.Client, error) {
+			return &http.Client{Transport: testTransport}, nil
+		},
+	}
+	var changed, deleted []string
+	state := &intacct.MemorySyncState{}
+	s := &intacct.Syncer{
+		Service:  sv,
+		Object:   "VENDOR",
+		Fields:   []string{"NAME", "recordno"},
+		State:    state,
+		PageSize: 2,
+		Sink: func(ctx context.Context, recs []intacct.ResultMap) error {
+			for _, rec := range recs {
+				changed = append(changed, rec.String("RECORDNO"))
+			}
+			return nil
+		},
+		DeleteSink: func(ctx context.Context, keys []string) error {
+			deleted = append(deleted, keys...)
+			return nil
+		},
+	}
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if strings.Join(changed, ",") != "1,2,3" || strings.Join(deleted, ",") != "9" {
+		t.Fatalf("first run expected changes 1,2,3 and deletes 9; got %v %v", changed, deleted)
+	}
+	cp, err := state.Load(ctx, "VENDOR")
+	if err != nil || cp == nil {
+		t.Fatalf("expected checkpoint; got %v", err)
+	}
+	if want := time.Date(2021, 1, 2, 10, 2, 0, 0, time.UTC); !cp.Changes.Watermark.Equal(want) {
+		t.Errorf("expected watermark %v; got %v", want, cp.Changes.Watermark)
+	}
+
+	changed, deleted = nil, nil
+	if err = s.Run(ctx); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if strings.Join(changed, ",") != "2" || len(deleted) != 0 {
+		t.Errorf("second run expected changes 2 and no deletes; got %v %v", changed, deleted)
+	}
+}
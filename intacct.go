@@ -3,9 +3,6 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package intacct implements the intacct webservices protocol
-// for accessing intacct data
-// https://developer.intacct.com/web-services/
 package intacct // import "github.com/jfcote87/intacct"
 
 import (
@@ -17,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strconv"
 	"sync"
 	"time"
@@ -71,6 +69,18 @@ type Service struct {
 	ControlIDFunc
 	// Set if a unique client is need.
 	HTTPClientFunc ctxclient.Func
+	// TimeZone is the company's time zone used to interpret datetimes
+	// returned without an offset and to format filter values (see
+	// Location).  nil is UTC.
+	TimeZone *time.Location
+	// StrictBool causes Response.Decode to return an error for boolean
+	// values not recognized by ParseBool rather than decoding false.
+	StrictBool bool
+	// StrictEnum causes requests to fail and Response.Decode to return an
+	// *EnumError for values of genobject enum types that are not valid.
+	// Functions and decoded values are walked for types with a Valid
+	// method.
+	StrictEnum bool
 }
 
 // Authenticator returns an interface{} that will xml marshal into
@@ -171,6 +181,9 @@ type AuthenticationConfig struct {
 	SenderPassword string   `xml:"sender_pwd" json:"sender_pwd,omitempty"` // Intacct Password
 	Login          *Login   `xml:"login,omitempty" json:"login,omitempty"`
 	Session        *Session `xml:"session,omitempty" json:"session,omitempty"`
+	// TimeZone is the IANA name of the company's time zone (optional).  When
+	// set, ServiceFrom... funcs load the zone into Service.TimeZone.
+	TimeZone string `xml:"time_zone,omitempty" json:"time_zone,omitempty"`
 }
 
 // ServiceFromConfigJSON returns a service from json representation.
@@ -222,6 +235,13 @@ func ServiceFromConfig(cfg AuthenticationConfig, opts ...ConfigOption) (*Service
 		SenderID: cfg.SenderID,
 		Password: cfg.SenderPassword,
 	}
+	if cfg.TimeZone != "" {
+		loc, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		sv.TimeZone = loc
+	}
 	for _, o := range opts {
 		o.setValue(sv)
 	}
@@ -383,6 +403,7 @@ func (sv *Service) ExecWithControl(ctx context.Context, cc *ControlConfig, f ...
 	if err = xml.NewDecoder(body).Decode(&reqResponse); err != nil {
 		return nil, err
 	}
+	reqResponse.setDecodeOptions(decodeOptions{strictBool: sv.StrictBool, strictEnum: sv.StrictEnum})
 	if checker, ok := sv.Authenticator.(AuthResponseChecker); ok {
 		checker.CheckResponse(ctx, reqResponse)
 	}
@@ -430,6 +451,11 @@ func (sv *Service) makeRequest(ctx context.Context, cc *ControlConfig, functions
 		return nil, err
 	}
 	control := sv.Control(ctx, cc)
+	if sv.StrictEnum {
+		if err := checkEnums(reflect.ValueOf(functions)); err != nil {
+			return nil, err
+		}
+	}
 	reqFuncs := make([]RequestFunction, 0, len(functions))
 	for _, f := range functions {
 		reqFuncs = append(reqFuncs, RequestFunction{
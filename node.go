@@ -0,0 +1,153 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Node is an ordered, lossless representation of an xml element.  Unlike
+// ResultMap, a Node keeps sibling order, namespaces, text between child
+// elements and repeated elements of differing shapes.  Use a Node (or
+// []*Node) in Response.Decode for reports and v2.1 responses, then call
+// ResultMap for map style access.
+//
+// Text is stored as child nodes with an empty Name.  Whitespace only text
+// is discarded.
+type Node struct {
+	Name     xml.Name
+	Attr     []xml.Attr
+	Text     string // character data of a text node
+	Children []*Node
+}
+
+// IsText returns true for text nodes
+func (n *Node) IsText() bool {
+	return n.Name.Local == ""
+}
+
+// UnmarshalXML builds the tree from the decoder
+func (n *Node) UnmarshalXML(d *xml.Decoder, s xml.StartElement) error {
+	n.Name = s.Name
+	n.Attr = append([]xml.Attr(nil), s.Attr...)
+	n.Children = nil
+	for {
+		tk, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tk.(type) {
+		case xml.StartElement:
+			child := &Node{}
+			if err = child.UnmarshalXML(d, t); err != nil {
+				return err
+			}
+			n.Children = append(n.Children, child)
+		case xml.CharData:
+			if strings.TrimSpace(string(t)) > "" {
+				n.Children = append(n.Children, &Node{Text: string(t)})
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// MarshalXML writes the tree.  The start element passed by the encoder
+// is ignored in favor of the node's name and attributes.
+func (n Node) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if n.IsText() {
+		return e.EncodeToken(xml.CharData(n.Text))
+	}
+	se := xml.StartElement{Name: n.Name, Attr: n.Attr}
+	if err := e.EncodeToken(se); err != nil {
+		return err
+	}
+	for _, c := range n.Children {
+		if err := c.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(se.End())
+}
+
+// Attribute returns the value of the named attribute
+func (n *Node) Attribute(name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Elements returns child elements in document order.  If name is not
+// empty, only elements with a matching local name are returned.
+func (n *Node) Elements(name string) []*Node {
+	var list []*Node
+	for _, c := range n.Children {
+		if !c.IsText() && (name == "" || c.Name.Local == name) {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// Element returns the first child element with the local name or nil
+func (n *Node) Element(name string) *Node {
+	for _, c := range n.Children {
+		if !c.IsText() && c.Name.Local == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// InnerText returns the concatenated text of the node and its
+// descendants.
+func (n *Node) InnerText() string {
+	if n.IsText() {
+		return n.Text
+	}
+	var sb strings.Builder
+	for _, c := range n.Children {
+		sb.WriteString(c.InnerText())
+	}
+	return sb.String()
+}
+
+// Walk calls fn for n and each descendant in document order.  Children
+// of a node are skipped when fn returns false.
+func (n *Node) Walk(fn func(*Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+// ResultMap converts the node to the ResultMap form.  Text surrounding
+// child elements is joined into the "" key.
+func (n *Node) ResultMap() ResultMap {
+	rm := make(ResultMap)
+	for _, a := range n.Attr {
+		rm["@"+a.Name.Local] = a.Value
+	}
+	var text string
+	for _, c := range n.Children {
+		if c.IsText() {
+			text += c.Text
+			continue
+		}
+		rm.addElement(c.Name.Local, c.ResultMap())
+	}
+	if text != "" {
+		rm[""] = text
+	}
+	return rm
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+func TestNode(t *testing.T) {
+	var tdata = `<report xmlns:r="urn:report" name="aging"><r:title>Aging</r:title>` +
+		`<row>Total</row><row><vendor>V1</vendor><due>10</due></row><note>see <b>detail</b> below</note>` +
+		`<row><vendor>V2</vendor><due>20</due></row></report>`
+	var n intacct.Node
+	if err := xml.Unmarshal([]byte(tdata), &n); err != nil {
+		t.Fatalf("unmarshal node: %v", err)
+	}
+	rows := n.Elements("row")
+	if len(rows) != 3 || rows[0].InnerText() != "Total" || rows[2].Element("vendor").InnerText() != "V2" {
+		t.Fatalf("expected 3 ordered rows; got %d", len(rows))
+	}
+	if title := n.Element("title"); title == nil || title.Name.Space != "urn:report" {
+		t.Errorf("expected title in urn:report namespace; got %#v", title)
+	}
+	if nm, _ := n.Attribute("name"); nm != "aging" {
+		t.Errorf("expected name attribute aging; got %s", nm)
+	}
+	if txt := n.Element("note").InnerText(); txt != "see detail below" {
+		t.Errorf("expected mixed content text; got %q", txt)
+	}
+	var names []string
+	n.Walk(func(c *intacct.Node) bool {
+		if !c.IsText() {
+			names = append(names, c.Name.Local)
+		}
+		return c.Name.Local != "row"
+	})
+	if len(names) != 7 {
+		t.Errorf("expected walk to visit 7 elements; got %v", names)
+	}
+
+	b, err := xml.Marshal(n)
+	if err != nil {
+		t.Fatalf("marshal node: %v", err)
+	}
+	var n2 intacct.Node
+	if err = xml.Unmarshal(b, &n2); err != nil {
+		t.Fatalf("unmarshal marshaled node %s: %v", b, err)
+	}
+	if len(n2.Elements("")) != 5 || n2.Elements("row")[1].Element("due").InnerText() != "10" {
+		t.Errorf("marshaled tree does not match %s", b)
+	}
+
+	rm := n.ResultMap()
+	if got := rm.Strings("row"); len(got) != 1 || got[0] != "Total" {
+		t.Errorf("expected row text Total; got %v", got)
+	}
+	if got := rm.Strings("row/vendor"); len(got) != 2 || got[1] != "V2" {
+		t.Errorf("expected row vendors V1, V2; got %v", got)
+	}
+	// decoding directly into a ResultMap must also keep all rows
+	var rm2 intacct.ResultMap
+	if err = xml.Unmarshal([]byte(tdata), &rm2); err != nil {
+		t.Fatalf("unmarshal resultmap: %v", err)
+	}
+	if vals, _ := rm2.Find("row"); len(vals) != 3 || rm2.Strings("row[0]")[0] != "Total" || rm2.Int("row[2]/due") != 20 {
+		t.Errorf("expected 3 rows in ResultMap; got %v", rm2["row"])
+	}
+
+	// empty elements are kept as empty entries
+	tdata = `<r><a>x</a><a/><b/><c/><c>y</c><d><e>1</e></d><d/></r>`
+	if err = xml.Unmarshal([]byte(tdata), &n); err != nil {
+		t.Fatalf("unmarshal node: %v", err)
+	}
+	for _, m := range []intacct.ResultMap{n.ResultMap(), nil} {
+		if m == nil {
+			if err = xml.Unmarshal([]byte(tdata), &m); err != nil {
+				t.Fatalf("unmarshal resultmap: %v", err)
+			}
+		}
+		if !reflect.DeepEqual(m["a"], []string{"x", ""}) || m["b"] != "" || !reflect.DeepEqual(m["c"], []string{"", "y"}) ||
+			!reflect.DeepEqual(m["d"], []intacct.ResultMap{{"e": "1"}, {}}) {
+			t.Errorf("expected empty entries; got %v", m)
+		}
+	}
+}
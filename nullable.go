@@ -0,0 +1,269 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// NullInt, NullFloat64 and NullBool distinguish a blank element from a
+// zero value.  Decoding a blank element leaves Valid false.  When
+// marshaling, an invalid value writes no element (so omitempty is not
+// needed), a valid value writes the value (including 0 and false) and a
+// value with Clear set writes an empty element which tells intacct to
+// clear the field in an update.  In json, invalid values are null.
+
+// NullInt is an integer that may be blank
+type NullInt struct {
+	Int   int64
+	Valid bool
+	Clear bool
+}
+
+// NewNullInt returns a valid NullInt
+func NewNullInt(i int64) NullInt {
+	return NullInt{Int: i, Valid: true}
+}
+
+// Val returns 0 for blank
+func (n NullInt) Val() int64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Int
+}
+
+// Ptr returns nil for blank
+func (n NullInt) Ptr() *int64 {
+	if !n.Valid {
+		return nil
+	}
+	i := n.Int
+	return &i
+}
+
+// MarshalXML writes the value, an empty element for Clear or nothing
+func (n NullInt) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalNullXML(e, start, n.Valid, n.Clear, strconv.FormatInt(n.Int, 10))
+}
+
+// UnmarshalXML sets Valid to false for blank elements and returns
+// an error for invalid values.
+func (n *NullInt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, err := decodeNullXML(d, start)
+	if err != nil || s == "" {
+		*n = NullInt{}
+		return err
+	}
+	return n.parse(s)
+}
+
+func (n *NullInt) parse(s string) error {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = NullInt{Int: i, Valid: true}
+	return nil
+}
+
+// MarshalJSON writes null for blank values
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatInt(n.Int, 10)), nil
+}
+
+// UnmarshalJSON accepts null, a number or a string
+func (n *NullInt) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalNullJSON(b)
+	if err != nil || s == "" {
+		*n = NullInt{}
+		return err
+	}
+	return n.parse(s)
+}
+
+// NullFloat64 is a float that may be blank
+type NullFloat64 struct {
+	Float64 float64
+	Valid   bool
+	Clear   bool
+}
+
+// NewNullFloat64 returns a valid NullFloat64
+func NewNullFloat64(f float64) NullFloat64 {
+	return NullFloat64{Float64: f, Valid: true}
+}
+
+// Val returns 0 for blank
+func (n NullFloat64) Val() float64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Float64
+}
+
+// Ptr returns nil for blank
+func (n NullFloat64) Ptr() *float64 {
+	if !n.Valid {
+		return nil
+	}
+	f := n.Float64
+	return &f
+}
+
+// MarshalXML writes the value, an empty element for Clear or nothing
+func (n NullFloat64) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalNullXML(e, start, n.Valid, n.Clear, strconv.FormatFloat(n.Float64, 'f', -1, 64))
+}
+
+// UnmarshalXML sets Valid to false for blank elements and returns
+// an error for invalid values.
+func (n *NullFloat64) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, err := decodeNullXML(d, start)
+	if err != nil || s == "" {
+		*n = NullFloat64{}
+		return err
+	}
+	return n.parse(s)
+}
+
+func (n *NullFloat64) parse(s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*n = NullFloat64{Float64: f, Valid: true}
+	return nil
+}
+
+// MarshalJSON writes null for blank values
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSON accepts null, a number or a string
+func (n *NullFloat64) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalNullJSON(b)
+	if err != nil || s == "" {
+		*n = NullFloat64{}
+		return err
+	}
+	return n.parse(s)
+}
+
+// NullBool is a bool that may be blank
+type NullBool struct {
+	Bool  bool
+	Valid bool
+	Clear bool
+}
+
+// NewNullBool returns a valid NullBool
+func NewNullBool(b bool) NullBool {
+	return NullBool{Bool: b, Valid: true}
+}
+
+// Val returns false for blank
+func (n NullBool) Val() bool {
+	return n.Valid && n.Bool
+}
+
+// Ptr returns nil for blank
+func (n NullBool) Ptr() *bool {
+	if !n.Valid {
+		return nil
+	}
+	b := n.Bool
+	return &b
+}
+
+// MarshalXML writes the value, an empty element for Clear or nothing
+func (n NullBool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalNullXML(e, start, n.Valid, n.Clear, strconv.FormatBool(n.Bool))
+}
+
+// UnmarshalXML sets Valid to false for blank elements and returns
+// an error for invalid values.
+func (n *NullBool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, err := decodeNullXML(d, start)
+	if err != nil || s == "" {
+		*n = NullBool{}
+		return err
+	}
+	return n.parse(s)
+}
+
+func (n *NullBool) parse(s string) error {
+	b, err := ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*n = NullBool{Bool: b, Valid: true}
+	return nil
+}
+
+// MarshalJSON writes null for blank values
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatBool(n.Bool)), nil
+}
+
+// UnmarshalJSON accepts null, a bool or a string
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalNullJSON(b)
+	if err != nil || s == "" {
+		*n = NullBool{}
+		return err
+	}
+	return n.parse(s)
+}
+
+func marshalNullXML(e *xml.Encoder, start xml.StartElement, valid, clear bool, val string) error {
+	switch {
+	case valid:
+		return e.EncodeElement(val, start)
+	case clear:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		return e.EncodeToken(start.End())
+	}
+	return nil
+}
+
+func decodeNullXML(d *xml.Decoder, start xml.StartElement) (string, error) {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// unmarshalNullJSON returns the text of a json value, "" for null
+func unmarshalNullJSON(b []byte) (string, error) {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return "", nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		err := json.Unmarshal(b, &s)
+		return strings.TrimSpace(s), err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+type nullTester struct {
+	XMLName     xml.Name            `xml:"VENDOR" json:"-"`
+	CreditLimit intacct.NullFloat64 `xml:"CREDITLIMIT" json:"credit_limit"`
+	Terms       intacct.NullInt     `xml:"TERMDAYS" json:"term_days"`
+	OnHold      intacct.NullBool    `xml:"ONHOLD" json:"on_hold"`
+}
+
+func TestNullable(t *testing.T) {
+	var v nullTester
+	if err := xml.Unmarshal([]byte(`<VENDOR><CREDITLIMIT></CREDITLIMIT><TERMDAYS>0</TERMDAYS><ONHOLD>false</ONHOLD></VENDOR>`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.CreditLimit.Valid || !v.Terms.Valid || v.Terms.Val() != 0 || !v.OnHold.Valid || v.OnHold.Val() {
+		t.Errorf("expected blank credit limit and valid zero values; got %#v", v)
+	}
+	if v.CreditLimit.Ptr() != nil || v.Terms.Ptr() == nil {
+		t.Errorf("expected nil credit limit ptr and non-nil terms ptr")
+	}
+	if err := xml.Unmarshal([]byte(`<VENDOR><TERMDAYS>N30</TERMDAYS></VENDOR>`), &v); err == nil {
+		t.Errorf("expected error for invalid int")
+	}
+
+	tests := []struct {
+		v    nullTester
+		xml  string
+		json string
+	}{
+		{
+			v:    nullTester{},
+			xml:  `<VENDOR></VENDOR>`,
+			json: `{"credit_limit":null,"term_days":null,"on_hold":null}`,
+		},
+		{
+			v:    nullTester{CreditLimit: intacct.NewNullFloat64(0), Terms: intacct.NewNullInt(30), OnHold: intacct.NewNullBool(false)},
+			xml:  `<VENDOR><CREDITLIMIT>0</CREDITLIMIT><TERMDAYS>30</TERMDAYS><ONHOLD>false</ONHOLD></VENDOR>`,
+			json: `{"credit_limit":0,"term_days":30,"on_hold":false}`,
+		},
+		{
+			v:    nullTester{CreditLimit: intacct.NullFloat64{Clear: true}, OnHold: intacct.NewNullBool(true)},
+			xml:  `<VENDOR><CREDITLIMIT></CREDITLIMIT><ONHOLD>true</ONHOLD></VENDOR>`,
+			json: `{"credit_limit":null,"term_days":null,"on_hold":true}`,
+		},
+	}
+	for i, tt := range tests {
+		if b, _ := xml.Marshal(tt.v); string(b) != tt.xml {
+			t.Errorf("test %d expected xml %s; got %s", i, tt.xml, b)
+		}
+		b, _ := json.Marshal(tt.v)
+		if string(b) != tt.json {
+			t.Errorf("test %d expected json %s; got %s", i, tt.json, b)
+		}
+		var v2 nullTester
+		if err := json.Unmarshal(b, &v2); err != nil {
+			t.Errorf("test %d json unmarshal: %v", i, err)
+		}
+		tt.v.CreditLimit.Clear = false
+		if v2 != tt.v {
+			t.Errorf("test %d expected %#v; got %#v", i, tt.v, v2)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"credit_limit":"125.5","term_days":"","on_hold":"true"}`), &v); err != nil ||
+		v.CreditLimit.Val() != 125.5 || v.Terms.Valid || !v.OnHold.Val() {
+		t.Errorf("expected string json values to parse; got %#v %v", v, err)
+	}
+}
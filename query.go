@@ -9,6 +9,8 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,18 +20,18 @@ import (
 // intacct documentation may be found at:
 // https://developer.intacct.com/web-services/queries/
 type Query struct {
-	XMLName         xml.Name      `xml:"query"`
-	Object          string        `xml:"object"`
-	Select          Select        `xml:"select"`
-	Filter          *Filter       `xml:"filter,omitempty"`
-	Sort            *QuerySort    `xml:"orderby,omitempty"`
-	Options         *QueryOptions `xml:"options,omitempty"`
-	PageSz          int           `xml:"pagesize,omitempty"`
-	Offset          int           `xml:"offset,omitempty"`
-	TransactionType string        `xml:"docparid,omitempty"`
+	XMLName         xml.Name      `xml:"query" json:"-" yaml:"-"`
+	Object          string        `xml:"object" json:"object" yaml:"object"`
+	Select          Select        `xml:"select" json:"select" yaml:"select"`
+	Filter          *Filter       `xml:"filter,omitempty" json:"filter,omitempty" yaml:"filter,omitempty"`
+	Sort            *QuerySort    `xml:"orderby,omitempty" json:"orderby,omitempty" yaml:"orderby,omitempty"`
+	Options         *QueryOptions `xml:"options,omitempty" json:"options,omitempty" yaml:"options,omitempty"`
+	PageSz          int           `xml:"pagesize,omitempty" json:"pagesize,omitempty" yaml:"pagesize,omitempty"`
+	Offset          int           `xml:"offset,omitempty" json:"offset,omitempty" yaml:"offset,omitempty"`
+	TransactionType string        `xml:"docparid,omitempty" json:"docparid,omitempty" yaml:"docparid,omitempty"`
 	// ControlID used for transaction marking. Leave blank for
 	// defaul behavior
-	ControlID string `xml:"-"`
+	ControlID string `xml:"-" json:"-" yaml:"-"`
 }
 
 // GetControlID fulfills intacct.Function so may be used in
@@ -62,27 +64,175 @@ func (q Query) GetAll(ctx context.Context, sv *Service, resultSlice interface{})
 	return nil
 }
 
-// Select determines fields to return for query
+// Select determines fields to return for query.  Additional fields for
+// an aggregate function are listed in CountFields, AvgFields, MinFields,
+// MaxFields and SumFields.  When aggregates are combined with Fields,
+// intacct groups the results by the values of Fields.  Decode aggregate
+// results into an AggregateRow or a struct with tags such as
+// `xml:"SUM.TOTALDUE"`.
 type Select struct {
+	Fields      []string `xml:"field" json:"fields,omitempty" yaml:"fields,omitempty"`
+	Count       string   `xml:"count,omitempty" json:"count,omitempty" yaml:"count,omitempty"`
+	Avg         string   `xml:"avg,omitempty" json:"avg,omitempty" yaml:"avg,omitempty"`
+	Min         string   `xml:"min,omitempty" json:"min,omitempty" yaml:"min,omitempty"`
+	Max         string   `xml:"max,omitempty" json:"max,omitempty" yaml:"max,omitempty"`
+	Sum         string   `xml:"sum,omitempty" json:"sum,omitempty" yaml:"sum,omitempty"`
+	CountFields []string `xml:"-" json:"count_fields,omitempty" yaml:"count_fields,omitempty"`
+	AvgFields   []string `xml:"-" json:"avg_fields,omitempty" yaml:"avg_fields,omitempty"`
+	MinFields   []string `xml:"-" json:"min_fields,omitempty" yaml:"min_fields,omitempty"`
+	MaxFields   []string `xml:"-" json:"max_fields,omitempty" yaml:"max_fields,omitempty"`
+	SumFields   []string `xml:"-" json:"sum_fields,omitempty" yaml:"sum_fields,omitempty"`
+}
+
+// selectXML is the xml form of Select listing all fields of each aggregate
+type selectXML struct {
 	Fields []string `xml:"field"`
-	Count  string   `xml:"count,omitempty"`
-	Avg    string   `xml:"avg,omitempty"`
-	Min    string   `xml:"min,omitempty"`
-	Max    string   `xml:"max,omitempty"`
-	Sum    string   `xml:"sum,omitempty"`
+	Count  []string `xml:"count,omitempty"`
+	Avg    []string `xml:"avg,omitempty"`
+	Min    []string `xml:"min,omitempty"`
+	Max    []string `xml:"max,omitempty"`
+	Sum    []string `xml:"sum,omitempty"`
+}
+
+func (s Select) xmlForm() selectXML {
+	return selectXML{
+		Fields: s.Fields,
+		Count:  aggregateFields(s.Count, s.CountFields),
+		Avg:    aggregateFields(s.Avg, s.AvgFields),
+		Min:    aggregateFields(s.Min, s.MinFields),
+		Max:    aggregateFields(s.Max, s.MaxFields),
+		Sum:    aggregateFields(s.Sum, s.SumFields),
+	}
+}
+
+func aggregateFields(fld string, flds []string) []string {
+	if fld == "" {
+		return flds
+	}
+	return append([]string{fld}, flds...)
+}
+
+func splitAggregateFields(flds []string) (string, []string) {
+	if len(flds) == 0 {
+		return "", nil
+	}
+	return flds[0], flds[1:]
+}
+
+// MarshalXML writes an element for each aggregate field
+func (s Select) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(s.xmlForm(), start)
+}
+
+// UnmarshalXML sets the first field of each aggregate in Count, Avg, Min,
+// Max or Sum and the remaining fields in the matching Fields list.
+func (s *Select) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var sx selectXML
+	if err := d.DecodeElement(&sx, &start); err != nil {
+		return err
+	}
+	*s = Select{Fields: sx.Fields}
+	s.Count, s.CountFields = splitAggregateFields(sx.Count)
+	s.Avg, s.AvgFields = splitAggregateFields(sx.Avg)
+	s.Min, s.MinFields = splitAggregateFields(sx.Min)
+	s.Max, s.MaxFields = splitAggregateFields(sx.Max)
+	s.Sum, s.SumFields = splitAggregateFields(sx.Sum)
+	return nil
+}
+
+// GroupBy appends fields used to group aggregate results to Fields.  The
+// receiver s is returned to allow chaining.
+func (s *Select) GroupBy(fields ...string) *Select {
+	s.Fields = append(s.Fields, fields...)
+	return s
+}
+
+// HasAggregates reports whether any aggregate function is selected
+func (s Select) HasAggregates() bool {
+	sx := s.xmlForm()
+	return len(sx.Count)+len(sx.Avg)+len(sx.Min)+len(sx.Max)+len(sx.Sum) > 0
+}
+
+// AggregateRow holds a result row of a query that selects aggregate functions.
+// Aggregate values are keyed by function and field (e.g. SUM.TOTALDUE) while
+// Group contains the values of the grouping fields.
+type AggregateRow struct {
+	Group      map[string]string
+	Aggregates map[string]string
+}
+
+var aggregateFuncs = map[string]bool{"COUNT": true, "AVG": true, "MIN": true, "MAX": true, "SUM": true}
+
+// UnmarshalXML separates aggregate values from grouping fields
+func (ar *AggregateRow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	ar.Group = make(map[string]string)
+	ar.Aggregates = make(map[string]string)
+	for {
+		tk, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tk.(type) {
+		case xml.StartElement:
+			var val string
+			if err = d.DecodeElement(&val, &t); err != nil {
+				return err
+			}
+			nm := t.Name.Local
+			if idx := strings.Index(nm, "."); idx > 0 && aggregateFuncs[strings.ToUpper(nm[:idx])] {
+				ar.Aggregates[strings.ToUpper(nm[:idx])+nm[idx:]] = val
+				continue
+			}
+			ar.Group[nm] = val
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (ar AggregateRow) float(fn, field string) Float64 {
+	f, _ := strconv.ParseFloat(ar.Aggregates[fn+"."+field], 64)
+	return Float64(f)
+}
+
+// Sum returns the sum of field, 0 if missing or not a number
+func (ar AggregateRow) Sum(field string) Float64 {
+	return ar.float("SUM", field)
+}
+
+// Avg returns the average of field, 0 if missing or not a number
+func (ar AggregateRow) Avg(field string) Float64 {
+	return ar.float("AVG", field)
+}
+
+// Count returns the record count for field, 0 if missing
+func (ar AggregateRow) Count(field string) Int {
+	return Int(ar.float("COUNT", field))
+}
+
+// Min returns the minimum value of field as returned by intacct which
+// may be a number or date.
+func (ar AggregateRow) Min(field string) string {
+	return ar.Aggregates["MIN."+field]
+}
+
+// Max returns the maximum value of field as returned by intacct which
+// may be a number or date.
+func (ar AggregateRow) Max(field string) string {
+	return ar.Aggregates["MAX."+field]
 }
 
 // QuerySort defined fields for sorting,
 type QuerySort struct {
-	XMLName xml.Name  `xml:"orderby"`
-	Fields  []OrderBy `xml:"order"`
+	XMLName xml.Name  `xml:"orderby" json:"-" yaml:"-"`
+	Fields  []OrderBy `xml:"order" json:"order" yaml:"order"`
 }
 
 // OrderBy describes sort conditions
 type OrderBy struct {
-	XMLName    xml.Name `xml:"order"`
-	Field      string   `xml:"field,omitempty"`
-	Descending bool     `xml:"descending,omitempty"`
+	XMLName    xml.Name `xml:"order" json:"-" yaml:"-"`
+	Field      string   `xml:"field,omitempty" json:"field" yaml:"field"`
+	Descending bool     `xml:"descending,omitempty" json:"descending,omitempty" yaml:"descending,omitempty"`
 }
 
 // MarshalXML used to create <descending> tag
@@ -98,8 +248,8 @@ func (o OrderBy) MarshalXML(e *xml.Encoder, s xml.StartElement) error {
 
 // QueryOptions set query flags
 type QueryOptions struct {
-	CaseInsensitive bool `xml:"caseinsensitive,omitempty"`
-	ShowPrivate     bool `xml:"showprivate,omitempty"`
+	CaseInsensitive bool `xml:"caseinsensitive,omitempty" json:"caseinsensitive,omitempty" yaml:"caseinsensitive,omitempty"`
+	ShowPrivate     bool `xml:"showprivate,omitempty" json:"showprivate,omitempty" yaml:"showprivate,omitempty"`
 }
 
 // NewFilter returns an initialized Filter pointer
@@ -213,11 +363,21 @@ func (f *Filter) GreaterThanOrEqualTo(field string, value string) *Filter {
 }
 
 // Between adds a date range filter for the field and begin and end dates to f's list of filters.  The
-// receiver value f is returned to allow chaining
+// receiver value f is returned to allow chaining.  Dates are taken from start and end in their own
+// locations; use BetweenIn to convert them to the company's time zone.
 func (f *Filter) Between(field string, start, end time.Time) *Filter {
 	return f.add("between", field, start.Format("01/02/2006"), end.Format("01/02/2006"))
 }
 
+// BetweenIn adds a date range filter using the dates of start and end in loc, usually
+// Service.Location.  The receiver value f is returned to allow chaining
+func (f *Filter) BetweenIn(field string, start, end time.Time, loc *time.Location) *Filter {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return f.Between(field, start.In(loc), end.In(loc))
+}
+
 // In adds a list filter for the field and values to f's list of filters.  The
 // receiver value f is returned to allow chaining
 func (f *Filter) In(field string, values ...string) *Filter {
@@ -256,11 +416,11 @@ func (f *Filter) IsNotNull(field string) *Filter {
 
 // ObjectRelationship describes relationship between objects
 type ObjectRelationship struct {
-	Path      string `xml:"OBJECTPATH"`
-	Name      string `xml:"OBJECTNAME"`
-	Lable     string `xml:"LABEL"`
-	Type      string `xml:"RELATIONSHIPTYPE"`
-	RelatedBy string `xml:"RELATEDBY"`
+	Path      string `xml:"OBJECTPATH" json:"path"`
+	Name      string `xml:"OBJECTNAME" json:"name"`
+	Lable     string `xml:"LABEL" json:"label,omitempty"`
+	Type      string `xml:"RELATIONSHIPTYPE" json:"type,omitempty"`
+	RelatedBy string `xml:"RELATEDBY" json:"related_by,omitempty"`
 }
 
 // ObjectField defines parameters of an intacct object (table)
@@ -129,3 +129,51 @@ func TestMarshal(t *testing.T) {
 		t.Errorf("expected marshal of %s; got %s", expect, b)
 	}
 }
+
+func TestAggregates(t *testing.T) {
+	var sel = intacct.Select{
+		Sum:       "TOTALDUE",
+		SumFields: []string{"TOTALPAID"},
+		Count:     "RECORDNO",
+	}
+	sx := intacct.Query{
+		Object: "ARINVOICE",
+		Select: *sel.GroupBy("CUSTOMERID"),
+	}
+	b, err := xml.Marshal(sx)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	expect := `<query><object>ARINVOICE</object><select><field>CUSTOMERID</field><count>RECORDNO</count><sum>TOTALDUE</sum><sum>TOTALPAID</sum></select></query>`
+	if expect != string(b) {
+		t.Errorf("expected marshal of %s; got %s", expect, b)
+	}
+
+	var decoded intacct.Query
+	if err = xml.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unmarshal query: %v", err)
+	}
+	if s := decoded.Select; s.Sum != "TOTALDUE" || len(s.SumFields) != 1 || s.SumFields[0] != "TOTALPAID" || s.Count != "RECORDNO" {
+		t.Errorf("unexpected decoded select %#v", s)
+	}
+	if s := sel.GroupBy("REGION"); len(s.Fields) != 2 || s.Fields[1] != "REGION" {
+		t.Errorf("expected GroupBy to append fields; got %v", s.Fields)
+	}
+
+	result := intacct.Result{Data: &intacct.ResultData{Payload: []byte(`
+		<ARINVOICE><CUSTOMERID>C1</CUSTOMERID><SUM.TOTALDUE>100.25</SUM.TOTALDUE><SUM.TOTALPAID>50</SUM.TOTALPAID><COUNT.RECORDNO>3</COUNT.RECORDNO></ARINVOICE>
+		<ARINVOICE><CUSTOMERID>C2</CUSTOMERID><SUM.TOTALDUE>-12.5</SUM.TOTALDUE><SUM.TOTALPAID></SUM.TOTALPAID><COUNT.RECORDNO>1</COUNT.RECORDNO></ARINVOICE>`)}}
+	var rows []intacct.AggregateRow
+	if err = result.Decode(&rows); err != nil {
+		t.Fatalf("decode aggregates: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows; got %d", len(rows))
+	}
+	if rows[0].Group["CUSTOMERID"] != "C1" || rows[0].Sum("TOTALDUE") != 100.25 || rows[0].Sum("TOTALPAID") != 50 || rows[0].Count("RECORDNO") != 3 {
+		t.Errorf("unexpected row 0 %#v", rows[0])
+	}
+	if rows[1].Group["CUSTOMERID"] != "C2" || rows[1].Sum("TOTALDUE") != -12.5 || rows[1].Sum("TOTALPAID") != 0 || rows[1].Avg("TOTALDUE") != 0 {
+		t.Errorf("unexpected row 1 %#v", rows[1])
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// filterDef is the serialized form of a Filter
+type filterDef struct {
+	Op      string   `json:"op,omitempty" yaml:"op,omitempty"`
+	Field   string   `json:"field,omitempty" yaml:"field,omitempty"`
+	Values  []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Filters []Filter `json:"filters,omitempty" yaml:"filters,omitempty"`
+}
+
+func (f Filter) def() filterDef {
+	return filterDef{
+		Op:      f.XMLName.Local,
+		Field:   f.Field,
+		Values:  f.Value,
+		Filters: f.Filters,
+	}
+}
+
+func (f *Filter) fromDef(def filterDef) {
+	op := strings.ToLower(def.Op)
+	if op == "" {
+		op = "filter"
+	}
+	*f = Filter{
+		XMLName: xml.Name{Local: op},
+		Field:   def.Field,
+		Value:   def.Values,
+		Filters: def.Filters,
+	}
+	f.setParents()
+}
+
+// setParents restores the parent links of f's descendants
+func (f *Filter) setParents() {
+	for i := range f.Filters {
+		f.Filters[i].parent = f
+		f.Filters[i].setParents()
+	}
+}
+
+// MarshalJSON encodes the filter in the serialized query format
+func (f Filter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.def())
+}
+
+// UnmarshalJSON decodes the serialized query format and restores
+// parent links.
+func (f *Filter) UnmarshalJSON(b []byte) error {
+	var def filterDef
+	if err := json.Unmarshal(b, &def); err != nil {
+		return err
+	}
+	f.fromDef(def)
+	return nil
+}
+
+// MarshalYAML fulfills the yaml.Marshaler interface
+func (f Filter) MarshalYAML() (interface{}, error) {
+	return f.def(), nil
+}
+
+// UnmarshalYAML fulfills the yaml.Unmarshaler interface of yaml.v2
+// (also accepted by yaml.v3) and restores parent links.
+func (f *Filter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var def filterDef
+	if err := unmarshal(&def); err != nil {
+		return err
+	}
+	f.fromDef(def)
+	return nil
+}
+
+// queryDef has no methods so it may be used to decode a Query
+type queryDef Query
+
+// fromDef sets q from a decoded query.  A top level filter with an op
+// (e.g. "and") is placed in a filter element as required by intacct.
+func (q *Query) fromDef(def queryDef) {
+	*q = Query(def)
+	if q.Filter == nil {
+		return
+	}
+	if q.Filter.XMLName.Local != "filter" {
+		q.Filter = &Filter{XMLName: xml.Name{Local: "filter"}, Filters: []Filter{*q.Filter}}
+	}
+	q.Filter.setParents()
+}
+
+// UnmarshalJSON decodes a serialized query.  The query is not validated,
+// so incomplete queries may be loaded.
+func (q *Query) UnmarshalJSON(b []byte) error {
+	var def queryDef
+	if err := json.Unmarshal(b, &def); err != nil {
+		return err
+	}
+	q.fromDef(def)
+	return nil
+}
+
+// UnmarshalYAML decodes a serialized query.  The query is not validated,
+// so incomplete queries may be loaded.
+func (q *Query) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var def queryDef
+	if err := unmarshal(&def); err != nil {
+		return err
+	}
+	q.fromDef(def)
+	return nil
+}
+
+// QueryFromJSON reads and validates a query saved as json.
+func QueryFromJSON(r io.Reader) (*Query, error) {
+	var q *Query
+	if err := json.NewDecoder(r).Decode(&q); err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return nil, errors.New("no query found")
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// filterValueCount lists the minimum and maximum number of values for each
+// filter operator. A maximum of -1 indicates no limit.
+var filterValueCount = map[string][2]int{
+	"equalto":              {1, 1},
+	"notequalto":           {1, 1},
+	"lessthan":             {1, 1},
+	"lessthanorequalto":    {1, 1},
+	"greaterthan":          {1, 1},
+	"greaterthanorequalto": {1, 1},
+	"like":                 {1, 1},
+	"notlike":              {1, 1},
+	"between":              {2, 2},
+	"in":                   {1, -1},
+	"notin":                {1, -1},
+	"isnull":               {0, 0},
+	"isnotnull":            {0, 0},
+}
+
+// Validate checks the structure of q: an object name and selected fields
+// are required, filter operators must be known with the proper number of
+// values, and sort fields may not be blank. Field names are not checked;
+// use ObjectType.ValidateQuery for that purpose.  A non-nil error is
+// of type QueryErrors.
+func (q Query) Validate() error {
+	v := &queryValidator{}
+	if q.Object == "" {
+		v.add("object", "", "object name required")
+	}
+	if len(q.Select.Fields) == 0 && !q.Select.HasAggregates() {
+		v.add("select", "", "no fields selected")
+	}
+	if q.Filter != nil {
+		v.checkFilterStructure(q.Filter, "filter")
+	}
+	if q.Sort != nil {
+		for _, o := range q.Sort.Fields {
+			if o.Field == "" {
+				v.add("orderby", "", "empty field name")
+			}
+		}
+	}
+	if q.PageSz < 0 || q.Offset < 0 {
+		v.add("pagesize", "", "pagesize and offset may not be negative")
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+func (v *queryValidator) checkFilterStructure(f *Filter, element string) {
+	op := f.XMLName.Local
+	switch op {
+	case "", "filter", "and", "or":
+		if f.Field != "" || len(f.Value) > 0 {
+			v.add(element, f.Field, "field and values not allowed on grouping filter")
+		}
+		if len(f.Filters) == 0 && op != "" && op != "filter" {
+			v.add(element, "", "no filters listed")
+		}
+		for i := range f.Filters {
+			v.checkFilterStructure(&f.Filters[i], "filter/"+f.Filters[i].XMLName.Local)
+		}
+		return
+	}
+	cnt, ok := filterValueCount[op]
+	if !ok {
+		v.add(element, f.Field, fmt.Sprintf("unknown operator %s", op))
+		return
+	}
+	if f.Field == "" {
+		v.add(element, "", "empty field name")
+	}
+	if len(f.Filters) > 0 {
+		v.add(element, f.Field, "nested filters not allowed")
+	}
+	if n := len(f.Value); n < cnt[0] || (cnt[1] >= 0 && n > cnt[1]) {
+		v.add(element, f.Field, fmt.Sprintf("invalid number of values %d", n))
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+	"gopkg.in/yaml.v2"
+)
+
+func TestQuery_JSON(t *testing.T) {
+	tm := time.Date(2020, 11, 01, 0, 0, 0, 0, time.UTC)
+	f := intacct.NewFilter()
+	f.Or().EqualTo("STATUS", "active").IsNull("PARENTID").And().Between("WHENDUE", tm, tm.AddDate(0, 1, 0)).In("TERMNAME", "N30", "N60")
+	q := intacct.Query{
+		Object:  "APBILL",
+		Select:  intacct.Select{Fields: []string{"RECORDNO", "VENDORID"}, Sum: "TOTALDUE"},
+		Filter:  f,
+		Sort:    &intacct.QuerySort{Fields: []intacct.OrderBy{{Field: "VENDORID", Descending: true}}},
+		Options: &intacct.QueryOptions{CaseInsensitive: true},
+		PageSz:  500,
+	}
+	b, err := json.Marshal(q)
+	if err != nil {
+		t.Fatalf("marshal json: %v", err)
+	}
+	q2, err := intacct.QueryFromJSON(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatalf("QueryFromJSON: %v", err)
+	}
+	x1, _ := xml.Marshal(q)
+	x2, _ := xml.Marshal(q2)
+	if string(x1) != string(x2) {
+		t.Errorf("expected %s; got %s", x1, x2)
+	}
+	or := &q2.Filter.Filters[0]
+	and := &or.Filters[2]
+	if or.Parent() != q2.Filter || and.Parent() != or || and.Filters[1].Parent() != and {
+		t.Errorf("parent links not restored")
+	}
+
+	y, err := yaml.Marshal(q)
+	if err != nil {
+		t.Fatalf("marshal yaml: %v", err)
+	}
+	var q3 intacct.Query
+	if err = yaml.Unmarshal(y, &q3); err != nil {
+		t.Fatalf("unmarshal yaml: %v", err)
+	}
+	if x3, _ := xml.Marshal(q3); string(x1) != string(x3) {
+		t.Errorf("expected %s; got %s from yaml %s", x1, x3, y)
+	}
+	or = &q3.Filter.Filters[0]
+	if or.Parent() != q3.Filter || or.Filters[2].Parent() != or {
+		t.Errorf("parent links not restored from yaml")
+	}
+
+	const saved = `
+object: VENDOR
+select:
+  fields: [VENDORID, NAME]
+  sum: TOTALDUE
+filter:
+  op: and
+  filters:
+  - {op: equalto, field: STATUS, values: [active]}
+  - {op: greaterthan, field: TOTALDUE, values: ["0"]}
+orderby:
+  order:
+  - {field: NAME, descending: true}
+`
+	var q4 intacct.Query
+	if err = yaml.Unmarshal([]byte(saved), &q4); err != nil {
+		t.Fatalf("unmarshal saved yaml: %v", err)
+	}
+	want := `<query><object>VENDOR</object><select><field>VENDORID</field><field>NAME</field><sum>TOTALDUE</sum></select>` +
+		`<filter><and><equalto><field>STATUS</field><value>active</value></equalto><greaterthan><field>TOTALDUE</field><value>0</value></greaterthan></and></filter>` +
+		`<orderby><order><field>NAME</field><descending></descending></order></orderby></query>`
+	if x4, _ := xml.Marshal(q4); string(x4) != want {
+		t.Errorf("expected %s; got %s", want, x4)
+	}
+}
+
+func TestQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		msg  string
+	}{
+		{
+			name: "valid",
+			json: `{"object":"VENDOR","select":{"fields":["NAME"]},"filter":{"op":"isnull","field":"PARENTID"}}`,
+		},
+		{
+			name: "no object",
+			json: `{"select":{"fields":["NAME"]}}`,
+			msg:  "object: object name required",
+		},
+		{
+			name: "no fields",
+			json: `{"object":"VENDOR","orderby":{"order":[{"field":""}]}}`,
+			msg:  "select: no fields selected; orderby: empty field name",
+		},
+		{
+			name: "bad filters",
+			json: `{"object":"VENDOR","select":{"count":"RECORDNO"},"filter":{"op":"and","filters":[
+				{"op":"between","field":"WHENDUE","values":["01/01/2020"]},
+				{"op":"equals","field":"NAME","values":["A"]},
+				{"op":"in","values":["A"]},
+				{"op":"or"}]}}`,
+			msg: "filter/between WHENDUE: invalid number of values 1; filter/equals NAME: unknown operator equals; " +
+				"filter/in: empty field name; filter/or: no filters listed",
+		},
+	}
+	for _, tt := range tests {
+		var q intacct.Query
+		// incomplete queries load without validation
+		if err := json.Unmarshal([]byte(tt.json), &q); err != nil {
+			t.Errorf("%s: unmarshal %v", tt.name, err)
+		}
+		_, err := intacct.QueryFromJSON(strings.NewReader(tt.json))
+		if tt.msg == "" {
+			if err != nil {
+				t.Errorf("%s: expected success; got %v", tt.name, err)
+			}
+			continue
+		}
+		if _, ok := err.(intacct.QueryErrors); !ok || err.Error() != tt.msg {
+			t.Errorf("%s: expected %s; got %v", tt.name, tt.msg, err)
+		}
+	}
+}
@@ -158,6 +158,20 @@ type Result struct {
 	ListType  *ListType     `xml:"listtype"`
 	Errors    []ErrorDetail `xml:"errormessage>error"`
 	Data      *ResultData   `xml:"data"`
+	opts      decodeOptions // settings of the Service returning the result
+}
+
+// decodeOptions holds Service settings checked by Result.Decode
+type decodeOptions struct {
+	strictBool bool
+	strictEnum bool
+}
+
+// setDecodeOptions passes the options to each result
+func (r *Response) setDecodeOptions(opts decodeOptions) {
+	for i := range r.Results {
+		r.Results[i].opts = opts
+	}
 }
 
 // ListType describes the start/ending/remaining records from
@@ -194,8 +208,25 @@ func (r Result) Decode(dst interface{}) error {
 		return errors.New("expected a non-nil ptr")
 	}
 
+	if err := r.decode(dv.Elem()); err != nil {
+		return err
+	}
+	if r.opts.strictBool {
+		isSlice := dv.Elem().Kind() == reflect.Slice
+		if err := checkBools(r.Data.Payload, dv.Elem().Type(), isSlice); err != nil {
+			return err
+		}
+	}
+	if r.opts.strictEnum {
+		return checkEnums(dv)
+	}
+	return nil
+}
+
+// decode unmarshals the payload into dv
+func (r Result) decode(dv reflect.Value) error {
 	dx := xml.NewDecoder(bytes.NewReader(r.Data.Payload))
-	if dv = dv.Elem(); dv.Kind() == reflect.Slice {
+	if dv.Kind() == reflect.Slice {
 		tk, err := dx.Token()
 		for elementCnt := 0; err == nil; elementCnt++ {
 			switch s := tk.(type) {
@@ -213,7 +244,7 @@ func (r Result) Decode(dst interface{}) error {
 		}
 		return err
 	}
-	return dx.Decode(dst)
+	return dx.DecodeElement(dv.Addr().Interface(), nil)
 }
 
 // ResultsError contains an array of errors corresponding to the functions
@@ -0,0 +1,174 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// JSONOptions produces a stable json encoding of ResultMaps.  Encoding a
+// ResultMap with encoding/json turns a repeated element into an array only
+// when it occurs more than once and leaves all values as strings.
+// JSONOptions instead writes listed elements as arrays regardless of count
+// and types values using an object definition.
+type JSONOptions struct {
+	// AttrPrefix is prepended to attribute names (default "@")
+	AttrPrefix string
+	// TextKey is the key of character data of elements having attributes
+	// (default "#text")
+	TextKey string
+	// Arrays lists elements that are always encoded as arrays.  An entry
+	// without a slash (e.g. CONTACT) matches the element at any depth while
+	// an entry with slashes (e.g. CONTACTS/CONTACT) matches the path from
+	// the top of the map.  A listed element missing from a map is written
+	// as an empty array, at the top of the map for entries without a slash
+	// and at the listed path, adding any missing parent objects, otherwise.
+	Arrays []string
+	// ObjectType is the Lookup definition of the encoded object (optional).
+	// Integer, number and boolean fields are written as json numbers and
+	// bools (any form accepted by ParseBool), and ONE2MANY and MANY2MANY
+	// relationships are always arrays.
+	// Nested fields are matched by dotted name (e.g. VENDOR.TOTALDUE).
+	ObjectType *ObjectType
+}
+
+// Marshal returns the json encoding of a ResultMap or []ResultMap
+func (o *JSONOptions) Marshal(v interface{}) ([]byte, error) {
+	switch val := v.(type) {
+	case ResultMap:
+		return json.Marshal(o.Convert(val))
+	case []ResultMap:
+		list := make([]map[string]interface{}, len(val))
+		for i := range val {
+			list[i] = o.Convert(val[i])
+		}
+		return json.Marshal(list)
+	}
+	return json.Marshal(v)
+}
+
+// Convert returns rm as a map ready for json encoding.  Use Convert when
+// embedding the result in a larger structure.
+func (o *JSONOptions) Convert(rm ResultMap) map[string]interface{} {
+	if o == nil {
+		o = &JSONOptions{}
+	}
+	return o.convert(rm, "", "")
+}
+
+func (o *JSONOptions) convert(rm ResultMap, path, dotted string) map[string]interface{} {
+	prefix, textKey := o.AttrPrefix, o.TextKey
+	if prefix == "" {
+		prefix = "@"
+	}
+	if textKey == "" {
+		textKey = "#text"
+	}
+	out := make(map[string]interface{}, len(rm))
+	for k, v := range rm {
+		if strings.HasPrefix(k, "@") {
+			out[prefix+k[1:]] = v
+			continue
+		}
+		if k == "" {
+			out[textKey] = o.typed(v, dotted)
+			continue
+		}
+		childPath, childDotted := k, k
+		if path != "" {
+			childPath, childDotted = path+"/"+k, dotted+"."+k
+		}
+		vals := expandValue(v)
+		list := make([]interface{}, len(vals))
+		for i, val := range vals {
+			if m, ok := val.(ResultMap); ok {
+				list[i] = o.convert(m, childPath, childDotted)
+				continue
+			}
+			list[i] = o.typed(val, childDotted)
+		}
+		switch {
+		case o.isArray(k, childPath) || len(list) > 1:
+			out[k] = list
+		case len(list) == 1:
+			out[k] = list[0]
+		}
+	}
+	o.addMissing(out, path)
+	return out
+}
+
+// addMissing adds empty arrays for the listed elements below path that
+// are missing from out.
+func (o *JSONOptions) addMissing(out map[string]interface{}, path string) {
+	for _, a := range o.Arrays {
+		rest := a
+		if path != "" {
+			if !strings.HasPrefix(a, path+"/") {
+				continue
+			}
+			rest = a[len(path)+1:]
+		}
+		name := strings.SplitN(rest, "/", 2)[0]
+		if _, ok := out[name]; ok {
+			continue
+		}
+		childPath := name
+		if path != "" {
+			childPath = path + "/" + name
+		}
+		if name == rest || o.isArray(name, childPath) {
+			out[name] = []interface{}{}
+			continue
+		}
+		child := make(map[string]interface{})
+		out[name] = child
+		o.addMissing(child, childPath)
+	}
+}
+
+// isArray returns true for elements listed in Arrays or relationships
+// to many records.
+func (o *JSONOptions) isArray(name, path string) bool {
+	for _, a := range o.Arrays {
+		if a == path || (a == name && !strings.Contains(a, "/")) {
+			return true
+		}
+	}
+	if o.ObjectType != nil && name == path {
+		if r, ok := o.ObjectType.Relationship(name); ok {
+			return strings.HasSuffix(strings.ToUpper(r.Type), "2MANY")
+		}
+	}
+	return false
+}
+
+// typed converts a string value to a json number or bool based upon
+// the field's data type.  Values that do not parse remain strings.
+func (o *JSONOptions) typed(v interface{}, field string) interface{} {
+	s, ok := v.(string)
+	if !ok || o.ObjectType == nil {
+		return v
+	}
+	fld, ok := o.ObjectType.Field(field)
+	if !ok {
+		return v
+	}
+	switch k := kindOf(fld.DataType); {
+	case k.isNumeric():
+		// json.Valid rejects forms such as NaN, +1 and .5
+		if _, err := strconv.ParseFloat(s, 64); err == nil && json.Valid([]byte(s)) {
+			return json.Number(s)
+		}
+	case k == kindBool:
+		if b, err := ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return s
+}
@@ -7,8 +7,10 @@ package intacct
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
-	"strconv"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 )
@@ -21,141 +23,134 @@ import (
 // "VENDOR":intacct.ResultMap{"NAME":intacct.ResultMap{"@type":"short", "":"Jim"}}}
 // while <VENDOR><NAME>Jim</NAME></VENDOR> becomes
 // "VENDOR":intacct.ResultMap{"NAME":"Jim"}}
+//
+// Accessors accept path expressions such as
+// "DISPLAYCONTACT/MAILADDRESS/CITY" (see Find).  Element order
+// is not kept; use Node when order matters.
 type ResultMap map[string]interface{}
 
+// elementKeys returns the element names of rm in sorted order
+func (rm ResultMap) elementKeys() []string {
+	keys := make([]string, 0, len(rm))
+	for k := range rm {
+		if k != "" && !strings.HasPrefix(k, "@") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ReadArray returns a slice of elements from an Element index. If the
 // value is nil or a single element, ReadArray returns an empty or a
-// single valued slice.  A string value will return an error.
+// single valued slice.  A string value will return an error.  name
+// may be a path (see Find).
 func (rm ResultMap) ReadArray(name string) ([]ResultMap, error) {
-	keys := strings.SplitN(name, "/", 2)
-	switch val := rm[keys[0]].(type) {
+	v, err := rm.value(name)
+	if err != nil {
+		return nil, err
+	}
+	switch val := v.(type) {
 	case nil:
 		return []ResultMap{}, nil
 	case []ResultMap:
-		if len(keys) > 1 {
-			return nil, fmt.Errorf("%s is an array not an resultMap", keys[0])
-		}
 		return val, nil
 	case ResultMap:
-		if len(keys) > 1 {
-			return val.ReadArray(keys[1])
-		}
 		return []ResultMap{val}, nil
 	}
-	return nil, fmt.Errorf("Not an ResultMap: %v", rm[name])
+	return nil, fmt.Errorf("Not an ResultMap: %v", v)
 }
 
 // String returns the string value of the Element index
 // when it is a string.  Rather than return an error, ReadSlice
-// returns an empty string for types other than a string.
+// returns an empty string for types other than a string.  name
+// may be a path (see Find).  Use StringE to read the character
+// data of elements with attributes or to detect errors.
 func (rm ResultMap) String(name string) string {
-	if s, ok := rm[name].(string); ok {
-		return s
-	}
-	return ""
+	v, _ := rm.value(name)
+	s, _ := v.(string)
+	return s
 }
 
 // Date returns the date (not datetime) from an
 // result map index.  It first checks for the
 // Year, Month and Day elements, then checks
-// for a string format of YYYY-MM-DD and finally
-// for MM-DD-YYYY
+// for a string format of YYYY-MM-DD, MM-DD-YYYY
+// and finally MM/DD/YYYY.  Use DateE to detect errors.
 func (rm ResultMap) Date(name string) *time.Time {
-	var tm time.Time
-
-	switch m := rm[name].(type) {
-	case ResultMap:
-		yr, mth, day := -1, -1, -1
-		for k, v := range m {
-			sval, _ := v.(string)
-			intVal, _ := strconv.Atoi(sval)
-			switch k {
-			case "Year", "year":
-				yr = int(intVal)
-			case "Month", "month":
-				mth = int(intVal)
-			case "Day", "day":
-				day = int(intVal)
-			}
-		}
-		if yr > 0 && mth > 0 && day > 0 {
-			tm = time.Date(yr, time.Month(mth), day, 0, 0, 0, 0, time.UTC)
-		}
-	case string:
-		var err error
-		if tm, err = time.Parse("2006-01-02", m); err != nil {
-			tm, _ = time.Parse("01-02-2006", m)
-		}
+	if tm, err := rm.DateE(name); err == nil && !tm.IsZero() {
+		return &tm
 	}
-	if tm.IsZero() {
-		return nil
-	}
-	return &tm
+	return nil
 }
 
-// Int parses the named field for an int64
+// Int parses the named field for an int64.  Decimal values
+// are truncated.  Use IntE to detect errors.
 func (rm ResultMap) Int(name string) int64 {
-	if s, ok := rm[name].(string); ok {
-
-		i, _ := strconv.ParseFloat(s, 64)
-		return int64(i)
-	}
-	return 0
+	f, _ := rm.FloatE(name)
+	return int64(f)
 }
 
-// Float parses the named field for a float64
+// Float parses the named field for a float64.  Use FloatE
+// to detect errors.
 func (rm ResultMap) Float(name string) float64 {
-	if s, ok := rm[name].(string); ok {
-		i, _ := strconv.ParseFloat(s, 64)
-		return i
-	}
-	return 0
+	f, _ := rm.FloatE(name)
+	return f
 }
 
 // Timestamp parses an RFC3339 formatted date string,
 // Errors are simply returned as nil.
 func (rm ResultMap) Timestamp(name string) *time.Time {
-	if s, ok := rm[name].(string); ok {
-		if tx, err := time.Parse(time.RFC3339, s); err == nil {
-			return &tx
-		}
+	if tx, err := rm.TimestampE(name); err == nil {
+		return &tx
 	}
 	return nil
 }
 
 // DateTime parses a get_list datetime string.
 func (rm ResultMap) DateTime(name string) *time.Time {
-	if s, ok := rm[name].(string); ok {
-		if tx, err := time.Parse("01/02/2006 15:04:05", s); err == nil {
-			return &tx
-		}
+	if tx, err := rm.DateTimeE(name); err == nil {
+		return &tx
 	}
 	return nil
 }
 
-// Bool parses true/false fields.  If not trueVals are
-// indicated, Bool checks for a  values of "true"
+// Bool parses true/false fields.  If no trueVals are
+// indicated, Bool accepts the variants of ParseBool (true, T, yes, active...)
 func (rm ResultMap) Bool(name string, trueVals ...string) bool {
-	s, _ := rm[name].(string)
-	if len(trueVals) == 0 {
-		return (s == "true")
-	}
-	for _, v := range trueVals {
-		if s == v {
-			return true
-		}
-	}
-	return false
+	b, _ := rm.BoolE(name, trueVals...)
+	return b
 }
 
-// StringArray returns a string slice
+// StringArray returns a string slice.  name may be a path (see Find).
 func (rm ResultMap) StringArray(name string) []string {
-	s, _ := rm[name].([]string)
+	v, _ := rm.value(name)
+	s, _ := v.([]string)
 	return s
 }
 
-// UnmarshalXML turns serialized XML into a map[string]interface{}
-func (rm ResultMap) UnmarshalXML(d *xml.Decoder, s xml.StartElement) error {
+// Strings returns the text of all values matching path (see Find).
+func (rm ResultMap) Strings(path string) []string {
+	vals, _ := rm.Find(path)
+	var s []string
+	for _, v := range vals {
+		if txt, ok := valueText(v); ok {
+			s = append(s, txt)
+		}
+	}
+	return s
+}
+
+// UnmarshalXML turns serialized XML into a map[string]interface{}.  A nil
+// map is allocated so that a *[]ResultMap may be used with Response.Decode.
+func (rm *ResultMap) UnmarshalXML(d *xml.Decoder, s xml.StartElement) error {
+	if *rm == nil {
+		*rm = make(ResultMap)
+	}
+	return rm.unmarshalXML(d, s)
+}
+
+func (rm ResultMap) unmarshalXML(d *xml.Decoder, s xml.StartElement) error {
 	// turn attributes in fields starting with @
 	for _, a := range s.Attr {
 		rm["@"+a.Name.Local] = a.Value
@@ -181,12 +176,20 @@ func (rm ResultMap) UnmarshalXML(d *xml.Decoder, s xml.StartElement) error {
 
 func (rm ResultMap) newElement(d *xml.Decoder, s xml.StartElement) error {
 	newEl := make(ResultMap)
-	if err := newEl.UnmarshalXML(d, s); err != nil {
+	if err := newEl.unmarshalXML(d, s); err != nil {
 		return err
 	}
-	tag := s.Name.Local
+	rm.addElement(s.Name.Local, newEl)
+	return nil
+}
+
+// addElement adds a decoded element to rm.  Repeated tags become slices,
+// and when a tag holds both text and nested elements, the text values
+// are stored as ResultMap{"": text}.  Empty elements are stored as
+// empty strings.
+func (rm ResultMap) addElement(tag string, newEl ResultMap) {
 	var sVal string
-	var isString bool
+	isString := len(newEl) == 0
 	if len(newEl) == 1 {
 		sVal, isString = newEl[""].(string)
 	}
@@ -198,17 +201,121 @@ func (rm ResultMap) newElement(d *xml.Decoder, s xml.StartElement) error {
 	case string:
 		if isString {
 			rm[tag] = []string{tVal, sVal}
+		} else {
+			rm[tag] = []ResultMap{{"": tVal}, newEl}
 		}
 	case []string:
 		if isString {
 			rm[tag] = append(tVal, sVal)
+		} else {
+			// a nested element following strings, promote the strings
+			// to character data elements so no values are lost
+			list := make([]ResultMap, 0, len(tVal)+1)
+			for _, sv := range tVal {
+				list = append(list, ResultMap{"": sv})
+			}
+			rm[tag] = append(list, newEl)
 		}
 	case nil:
 		if isString {
 			rm[tag] = sVal
-		} else if len(newEl) > 0 {
+		} else {
 			rm[tag] = newEl
 		}
 	}
-	return nil
+}
+
+// MarshalXML serializes the map so that a ResultMap may be used as a
+// Writer payload (e.g. Create("VENDOR", rm)).  Keys beginning with @ are
+// written as attributes, the "" key as character data and all other keys
+// as elements in sorted order.  Values may be strings, ResultMaps, slices
+// of either or any value accepted by xml.Marshal.  Use a Node as the
+// payload when elements must be sent in a particular order.
+func (rm ResultMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "" {
+		start.Name.Local = "ResultMap"
+	}
+	for k, v := range rm {
+		if strings.HasPrefix(k, "@") {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: k[1:]}, Value: fmt.Sprint(v)})
+		}
+	}
+	keys := rm.elementKeys()
+	sort.Slice(start.Attr, func(i, j int) bool {
+		return start.Attr[i].Name.Local < start.Attr[j].Name.Local
+	})
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if s, ok := rm[""]; ok {
+		if err := e.EncodeToken(xml.CharData(fmt.Sprint(s))); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys {
+		if err := e.EncodeElement(rm[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Decode copies the map into v, a pointer to a struct (or slice of
+// structs), using v's xml tags.  intacct types such as Date, Datetime,
+// Int, Float64 and Bool as well as ",any" CustomField slices are decoded
+// as though v were unmarshalled from the original response.
+func (rm ResultMap) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("Decode requires a non-nil pointer")
+	}
+	b, err := xml.Marshal(xmlElement{name: xmlStructName(rv.Type()), value: rm})
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(b, v)
+}
+
+// ToResultMap converts v to a ResultMap using its xml tags.  This is the
+// reverse of ResultMap.Decode.
+func ToResultMap(v interface{}) (ResultMap, error) {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var rm ResultMap
+	if err = xml.Unmarshal(b, &rm); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
+
+// xmlElement marshals value using the element name
+type xmlElement struct {
+	name  string
+	value interface{}
+}
+
+// MarshalXML encodes the value using x.name
+func (x xmlElement) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(x.value, xml.StartElement{Name: xml.Name{Local: x.name}})
+}
+
+// xmlStructName returns the element name of the XMLName field of t
+// allowing Decode to satisfy the name check of xml.Unmarshal.
+func xmlStructName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName("XMLName"); ok {
+			if nm := strings.Split(f.Tag.Get("xml"), ",")[0]; nm != "" {
+				if idx := strings.LastIndex(nm, " "); idx >= 0 {
+					nm = nm[idx+1:]
+				}
+				return nm
+			}
+		}
+	}
+	return "ResultMap"
 }
@@ -190,3 +190,299 @@ func isNilDt(dt *time.Time) interface{} {
 	}
 	return *dt
 }
+
+func TestResultMap_Find(t *testing.T) {
+	var tdata = `<VENDOR id="abc">
+	<NAME type="short">Acme</NAME>
+	<DISPLAYCONTACT><MAILADDRESS><CITY>Carmel</CITY><ZIP>46032</ZIP></MAILADDRESS></DISPLAYCONTACT>
+	<CONTACTS>
+	<CONTACT id="123"><NAME>Contact1</NAME><CITY>Carmel</CITY><BALANCE>10.5</BALANCE></CONTACT>
+	<CONTACT id="124"><NAME>Contact2</NAME><CITY>Indianapolis</CITY><BALANCE>20</BALANCE></CONTACT>
+	<CONTACT id="125"><NAME>Contact3</NAME><CITY>Indianapolis</CITY></CONTACT>
+	</CONTACTS>
+	<TAG>A</TAG><TAG>B</TAG>
+	</VENDOR>`
+	var rm intacct.ResultMap
+	if err := xml.Unmarshal([]byte(tdata), &rm); err != nil {
+		t.Fatalf("unmarshal resultMap failed %v", err)
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "DISPLAYCONTACT/MAILADDRESS/CITY", want: []string{"Carmel"}},
+		{path: "@id", want: []string{"abc"}},
+		{path: "NAME", want: []string{"Acme"}},
+		{path: "NAME/@type", want: []string{"short"}},
+		{path: "CONTACTS/CONTACT/NAME", want: []string{"Contact1", "Contact2", "Contact3"}},
+		{path: "CONTACTS/CONTACT[1]/NAME", want: []string{"Contact2"}},
+		{path: "CONTACTS/CONTACT[-1]/@id", want: []string{"125"}},
+		{path: "CONTACTS/CONTACT[CITY='Indianapolis']/NAME", want: []string{"Contact2", "Contact3"}},
+		{path: "CONTACTS/CONTACT[CITY!='Indianapolis']/NAME", want: []string{"Contact1"}},
+		{path: "CONTACTS/CONTACT[@id=\"124\"]/CITY", want: []string{"Indianapolis"}},
+		{path: "CONTACTS/CONTACT[BALANCE]/NAME", want: []string{"Contact1", "Contact2"}},
+		{path: "CONTACTS/CONTACT[CITY='Indianapolis'][0]/NAME", want: []string{"Contact2"}},
+		{path: "DISPLAYCONTACT/MAILADDRESS/*", want: []string{"Carmel", "46032"}},
+		{path: "TAG", want: []string{"A", "B"}},
+		{path: "TAG[.='B']", want: []string{"B"}},
+		{path: "CONTACTS/CONTACT[5]/NAME"},
+		{path: "MISSING/NAME"},
+	}
+	for _, tt := range tests {
+		if got := rm.Strings(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s expected %v; got %v", tt.path, tt.want, got)
+		}
+	}
+	if rm.String("CONTACTS/CONTACT[@id='124']/NAME") != "Contact2" || rm.Float("CONTACTS/CONTACT[1]/BALANCE") != 20 ||
+		rm.Int("DISPLAYCONTACT/MAILADDRESS/ZIP") != 46032 {
+		t.Errorf("typed accessors did not resolve paths")
+	}
+	// String and StringArray only return values of their own type
+	if rm.String("NAME") != "" || rm.String("TAG") != "" || rm.StringArray("CONTACTS/CONTACT[0]/NAME") != nil ||
+		!reflect.DeepEqual(rm.StringArray("TAG"), []string{"A", "B"}) {
+		t.Errorf("String and StringArray expected values of matching type only")
+	}
+	if vals, err := rm.ReadArray("CONTACTS/CONTACT[CITY='Indianapolis']"); err != nil || len(vals) != 1 || vals[0].String("NAME") != "Contact2" {
+		t.Errorf("ReadArray expected Contact2; got %v %v", vals, err)
+	}
+	if vals, err := rm.ReadArray("DISPLAYCONTACT/MAILADDRESS"); err != nil || len(vals) != 1 || vals[0].String("ZIP") != "46032" {
+		t.Errorf("ReadArray expected MAILADDRESS; got %v %v", vals, err)
+	}
+	if _, err := rm.ReadArray("DISPLAYCONTACT/MAILADDRESS/CITY"); err == nil {
+		t.Errorf("ReadArray expected error for string value")
+	}
+	for _, path := range []string{"CONTACTS//NAME", "CONTACT[", "CONTACT[CITY=Carmel]"} {
+		if _, err := rm.Find(path); err == nil {
+			t.Errorf("%s expected error", path)
+		}
+	}
+}
+
+func TestResultMap_Strict(t *testing.T) {
+	var tdata = `<APBILL>
+	<RECORDNO>12</RECORDNO>
+	<TOTALDUE>1,250.50</TOTALDUE>
+	<TOTALPAID>99.2</TOTALPAID>
+	<WHENDUE>12/31/2020</WHENDUE>
+	<WHENMODIFIED>2020-12-31 10:00:00</WHENMODIFIED>
+	<ONHOLD>maybe</ONHOLD>
+	<VENDOR><NAME>Acme</NAME></VENDOR>
+	</APBILL>`
+	var rm intacct.ResultMap
+	if err := xml.Unmarshal([]byte(tdata), &rm); err != nil {
+		t.Fatalf("unmarshal resultMap failed %v", err)
+	}
+	kindOf := func(err error) intacct.FieldErrorKind {
+		if fe, ok := err.(*intacct.FieldError); ok {
+			return fe.Kind
+		}
+		return 0
+	}
+	if i, err := rm.IntE("RECORDNO"); err != nil || i != 12 {
+		t.Errorf("IntE(RECORDNO) expected 12; got %d %v", i, err)
+	}
+	if _, err := rm.IntE("MISSING"); kindOf(err) != intacct.FieldMissing || !intacct.IsFieldMissing(err) {
+		t.Errorf("IntE(MISSING) expected missing error; got %v", err)
+	}
+	if _, err := rm.StringE("VENDOR"); kindOf(err) != intacct.FieldWrongKind {
+		t.Errorf("StringE(VENDOR) expected wrong kind error; got %v", err)
+	}
+	if _, err := rm.FloatE("TOTALDUE"); kindOf(err) != intacct.FieldParse ||
+		err.Error() != `TOTALDUE: unable to parse "1,250.50": invalid syntax` {
+		t.Errorf("FloatE(TOTALDUE) expected parse error; got %v", err)
+	}
+	if _, err := rm.IntE("TOTALPAID"); kindOf(err) != intacct.FieldParse {
+		t.Errorf("IntE(TOTALPAID) expected parse error; got %v", err)
+	}
+	if rm.Int("TOTALPAID") != 99 {
+		t.Errorf("Int(TOTALPAID) expected 99; got %d", rm.Int("TOTALPAID"))
+	}
+	if _, err := rm.StringE("VENDOR["); kindOf(err) != intacct.FieldInvalidPath {
+		t.Errorf("StringE(VENDOR[) expected invalid path error; got %v", err)
+	}
+
+	fc := rm.Collect()
+	fc.Require("RECORDNO", "VENDORID")
+	fc.Int("RECORDNO")
+	fc.Float("TOTALDUE")
+	fc.Date("WHENDUE")
+	fc.DateTime("WHENMODIFIED")
+	fc.Bool("ONHOLD")
+	fc.String("VENDOR/NAME")
+	fc.Float("TOTALENTERED")
+	err := fc.Err()
+	errs, ok := err.(intacct.FieldErrors)
+	if !ok || len(errs) != 4 {
+		t.Fatalf("expected 4 field errors; got %v", err)
+	}
+	for i, k := range []intacct.FieldErrorKind{intacct.FieldMissing, intacct.FieldParse, intacct.FieldParse, intacct.FieldParse} {
+		if errs[i].Kind != k {
+			t.Errorf("error %d expected kind %d; got %v", i, k, errs[i])
+		}
+	}
+	if errs[0].Path != "VENDORID" || errs[3].Path != "ONHOLD" {
+		t.Errorf("expected errors for VENDORID and ONHOLD; got %v", errs)
+	}
+}
+
+type decodeTester struct {
+	XMLName      xml.Name              `xml:"APBILL"`
+	RecordNo     intacct.Int           `xml:"RECORDNO"`
+	Total        intacct.Float64       `xml:"TOTALDUE"`
+	OnHold       intacct.Bool          `xml:"ONHOLD"`
+	WhenDue      intacct.Date          `xml:"WHENDUE"`
+	Modified     intacct.Datetime      `xml:"WHENMODIFIED"`
+	VendorName   string                `xml:"VENDOR>NAME"`
+	Status       string                `xml:"status,attr"`
+	Lines        []string              `xml:"LINE"`
+	CustomFields []intacct.CustomField `xml:",any"`
+}
+
+func TestResultMap_Decode(t *testing.T) {
+	rm := intacct.ResultMap{
+		"@status":      "posted",
+		"RECORDNO":     "42",
+		"TOTALDUE":     "125.5",
+		"ONHOLD":       "true",
+		"WHENDUE":      "12/31/2020",
+		"WHENMODIFIED": "12/30/2020 10:11:12",
+		"VENDOR":       intacct.ResultMap{"NAME": "Acme & Co"},
+		"LINE":         []string{"1", "2"},
+		"REGION":       "West",
+	}
+	var bill decodeTester
+	if err := rm.Decode(&bill); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if bill.RecordNo != 42 || bill.Total != 125.5 || !bill.OnHold.Val() || bill.WhenDue.String() != "2020-12-31" ||
+		bill.Modified.String() != "2020-12-30T10:11:12Z" || bill.VendorName != "Acme & Co" || bill.Status != "posted" ||
+		!reflect.DeepEqual(bill.Lines, []string{"1", "2"}) {
+		t.Errorf("unexpected decode result %#v", bill)
+	}
+	if len(bill.CustomFields) != 1 || bill.CustomFields[0].Name != "REGION" || bill.CustomFields[0].Value != "West" {
+		t.Errorf("expected custom field REGION; got %v", bill.CustomFields)
+	}
+	if err := rm.Decode(bill); err == nil {
+		t.Errorf("expected error decoding into non-pointer")
+	}
+
+	b, err := xml.Marshal(intacct.Create("APBILL", rm))
+	if err != nil {
+		t.Fatalf("marshal writer: %v", err)
+	}
+	want := `<create><APBILL status="posted"><LINE>1</LINE><LINE>2</LINE><ONHOLD>true</ONHOLD><RECORDNO>42</RECORDNO>` +
+		`<REGION>West</REGION><TOTALDUE>125.5</TOTALDUE><VENDOR><NAME>Acme &amp; Co</NAME></VENDOR>` +
+		`<WHENDUE>12/31/2020</WHENDUE><WHENMODIFIED>12/30/2020 10:11:12</WHENMODIFIED></APBILL></create>`
+	if string(b) != want {
+		t.Errorf("expected %s; got %s", want, b)
+	}
+
+	rm2, err := intacct.ToResultMap(bill)
+	if err != nil {
+		t.Fatalf("ToResultMap: %v", err)
+	}
+	if rm2.String("@status") != "posted" || rm2.String("VENDOR/NAME") != "Acme & Co" || rm2.String("WHENDUE") != "2020-12-31" ||
+		rm2.String("REGION") != "West" || rm2.Int("RECORDNO") != 42 || len(rm2.StringArray("LINE")) != 2 {
+		t.Errorf("unexpected ToResultMap result %v", rm2)
+	}
+	if !reflect.DeepEqual(rm2["VENDOR"], intacct.ResultMap{"NAME": "Acme & Co"}) {
+		t.Errorf("expected only decoded keys; got %#v", rm2["VENDOR"])
+	}
+}
+
+func TestResultMap_MarshalXML(t *testing.T) {
+	tdata := `<VENDOR id="abc"><VENDORID>V100</VENDORID><NAME type="short">Acme</NAME>` +
+		`<DISPLAYCONTACT><PRINTAS>Acme Inc</PRINTAS><MAILADDRESS><ZIP>46032</ZIP><CITY>Carmel</CITY></MAILADDRESS></DISPLAYCONTACT>` +
+		`<TAG>B</TAG><TAG>A</TAG><CONTACT><NAME>C1</NAME></CONTACT><CONTACT><NAME>C2</NAME></CONTACT><ACTIVE>true</ACTIVE></VENDOR>`
+	var rm intacct.ResultMap
+	if err := xml.Unmarshal([]byte(tdata), &rm); err != nil {
+		t.Fatalf("unmarshal resultMap failed %v", err)
+	}
+	if len(rm) != 7 {
+		t.Errorf("expected only element and attribute keys; got %v", rm)
+	}
+	want := `<VENDOR id="abc"><ACTIVE>true</ACTIVE><CONTACT><NAME>C1</NAME></CONTACT><CONTACT><NAME>C2</NAME></CONTACT>` +
+		`<DISPLAYCONTACT><MAILADDRESS><CITY>Carmel</CITY><ZIP>46032</ZIP></MAILADDRESS><PRINTAS>Acme Inc</PRINTAS></DISPLAYCONTACT>` +
+		`<NAME type="short">Acme</NAME><TAG>B</TAG><TAG>A</TAG><VENDORID>V100</VENDORID></VENDOR>`
+	b, err := xml.Marshal(xmlElement{"VENDOR", rm})
+	if err != nil || string(b) != want {
+		t.Errorf("expected %s; got %s %v", want, b, err)
+	}
+	if got := rm.Strings("DISPLAYCONTACT/MAILADDRESS/*"); !reflect.DeepEqual(got, []string{"Carmel", "46032"}) {
+		t.Errorf("expected wildcard in key order; got %v", got)
+	}
+	// a Node keeps the decoded order
+	var n intacct.Node
+	if err = xml.Unmarshal([]byte(tdata), &n); err != nil {
+		t.Fatalf("unmarshal node failed %v", err)
+	}
+	if b, err = xml.Marshal(intacct.Create("VENDOR", n)); err != nil || string(b) != "<create>"+tdata+"</create>" {
+		t.Errorf("expected node payload in decoded order; got %s %v", b, err)
+	}
+}
+
+// xmlElement marshals value as an element named name
+type xmlElement struct {
+	name  string
+	value interface{}
+}
+
+func (x xmlElement) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(x.value, xml.StartElement{Name: xml.Name{Local: x.name}})
+}
+
+func TestJSONOptions(t *testing.T) {
+	ot := getVendorLookup(t)
+	ot.Relationships = append(ot.Relationships, intacct.ObjectRelationship{Path: "CONTACTS", Name: "CONTACT", Type: "ONE2MANY"})
+	var tdata = `<VENDOR id="abc">
+	<RECORDNO>12</RECORDNO>
+	<NAME type="short">Acme</NAME>
+	<TOTALDUE>125.50</TOTALDUE>
+	<ONHOLD>false</ONHOLD>
+	<REGION>0012</REGION>
+	<CONTACTS><NAME>Contact1</NAME></CONTACTS>
+	<TAGS><TAG>A</TAG></TAGS>
+	</VENDOR>`
+	var rm intacct.ResultMap
+	if err := xml.Unmarshal([]byte(tdata), &rm); err != nil {
+		t.Fatalf("unmarshal resultMap failed %v", err)
+	}
+	tests := []struct {
+		name string
+		opts *intacct.JSONOptions
+		want string
+	}{
+		{
+			name: "defaults",
+			opts: &intacct.JSONOptions{},
+			want: `{"@id":"abc","CONTACTS":{"NAME":"Contact1"},"NAME":{"#text":"Acme","@type":"short"},"ONHOLD":"false",` +
+				`"RECORDNO":"12","REGION":"0012","TAGS":{"TAG":"A"},"TOTALDUE":"125.50"}`,
+		},
+		{
+			name: "schema",
+			opts: &intacct.JSONOptions{AttrPrefix: "_", TextKey: "value", Arrays: []string{"TAGS/TAG", "LINES/LINE", "LINES/ITEMS/ITEM", "CONTACTS/PHONE"}, ObjectType: ot},
+			want: `{"CONTACTS":[{"NAME":"Contact1","PHONE":[]}],"LINES":{"ITEMS":{"ITEM":[]},"LINE":[]},"NAME":{"_type":"short","value":"Acme"},"ONHOLD":false,` +
+				`"RECORDNO":12,"REGION":"0012","TAGS":{"TAG":["A"]},"TOTALDUE":125.50,"_id":"abc"}`,
+		},
+	}
+	for _, tt := range tests {
+		b, err := tt.opts.Marshal(rm)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s: expected %s; got %s", tt.name, tt.want, b)
+		}
+	}
+	for val, want := range map[string]string{"T": "true", "Yes": "true", "inactive": "false", " n ": "false", "maybe": `"maybe"`} {
+		b, err := (&intacct.JSONOptions{ObjectType: ot}).Marshal(intacct.ResultMap{"ONHOLD": val})
+		if want = `{"ONHOLD":` + want + `}`; err != nil || string(b) != want {
+			t.Errorf("ONHOLD %q expected %s; got %s %v", val, want, b, err)
+		}
+	}
+	b, _ := (&intacct.JSONOptions{Arrays: []string{"TAG"}}).Marshal([]intacct.ResultMap{{"TAG": "A"}, {"TAG": []string{"B", "C"}}, {"NAME": "D"}})
+	if want := `[{"TAG":["A"]},{"TAG":["B","C"]},{"NAME":"D","TAG":[]}]`; string(b) != want {
+		t.Errorf("expected %s; got %s", want, b)
+	}
+}
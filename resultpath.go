@@ -0,0 +1,298 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ResultMap paths select values from nested elements.  A path is a list of
+// steps separated by slashes where each step is one of
+//
+//	NAME            child element NAME
+//	*               all child elements (in key order)
+//	@attr           attribute attr
+//	NAME[n]         nth NAME element (0 based, negative values count from the end)
+//	NAME[KEY='v']   NAME elements whose KEY child (or @attr) equals v
+//	NAME[KEY!='v']  NAME elements whose KEY child does not equal v
+//	NAME[KEY]       NAME elements having a KEY child
+//
+// Predicates and indexes may be combined (e.g. CONTACT[CITY='Carmel'][0])
+// and a predicate key of . tests the element's own text.  Repeated
+// elements ([]ResultMap and []string) are expanded so that each step
+// applies to every value, e.g. CONTACTS/CONTACT/NAME returns the
+// name of every contact.  Every ResultMap accessor accepts a path.
+// When a path matches several values, accessors use the first.  Find and
+// Strings return all matching values.
+
+// pathStep is a single parsed step of a path
+type pathStep struct {
+	name  string
+	preds []pathPred
+}
+
+// pathPred is either an index or a key comparison
+type pathPred struct {
+	isIndex bool
+	index   int
+	key     string
+	op      string // "", "=" or "!="
+	value   string
+}
+
+// parsePath splits path into steps
+func parsePath(path string) ([]pathStep, error) {
+	var steps []pathStep
+	for _, s := range splitPath(path) {
+		st, err := parseStep(s)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %v", path, err)
+		}
+		steps = append(steps, st)
+	}
+	return steps, nil
+}
+
+// splitPath splits on slashes outside of brackets and quotes
+func splitPath(path string) []string {
+	var parts []string
+	var depth int
+	var quote rune
+	start := 0
+	for i, r := range path {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case r == '/' && depth == 0:
+			parts = append(parts, path[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, path[start:])
+}
+
+func parseStep(s string) (pathStep, error) {
+	idx := strings.Index(s, "[")
+	if idx < 0 {
+		idx = len(s)
+	}
+	st := pathStep{name: s[:idx]}
+	if st.name == "" || st.name == "@" {
+		return st, fmt.Errorf("empty step")
+	}
+	rest := s[idx:]
+	for rest != "" {
+		end := predicateEnd(rest)
+		if rest[0] != '[' || end < 0 {
+			return st, fmt.Errorf("invalid predicate %s", rest)
+		}
+		p, err := parsePredicate(strings.TrimSpace(rest[1:end]))
+		if err != nil {
+			return st, err
+		}
+		st.preds = append(st.preds, p)
+		rest = rest[end+1:]
+	}
+	return st, nil
+}
+
+// predicateEnd returns the index of the bracket closing s[0]
+func predicateEnd(s string) int {
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePredicate(s string) (pathPred, error) {
+	if s == "" {
+		return pathPred{}, fmt.Errorf("empty predicate")
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return pathPred{isIndex: true, index: n}, nil
+	}
+	var p pathPred
+	if idx := strings.Index(s, "!="); idx > 0 {
+		p.key, p.op, p.value = s[:idx], "!=", s[idx+2:]
+	} else if idx := strings.Index(s, "="); idx > 0 {
+		p.key, p.op, p.value = s[:idx], "=", s[idx+1:]
+	} else {
+		p.key = s
+	}
+	p.key = strings.TrimSpace(p.key)
+	if p.op != "" {
+		v := strings.TrimSpace(p.value)
+		if len(v) < 2 || (v[0] != '\'' && v[0] != '"') || v[len(v)-1] != v[0] {
+			return p, fmt.Errorf("predicate value must be quoted: %s", s)
+		}
+		p.value = v[1 : len(v)-1]
+	}
+	return p, nil
+}
+
+// Find returns all values matching path.  Values are strings or
+// ResultMaps.
+func (rm ResultMap) Find(path string) ([]interface{}, error) {
+	if v, ok := rm[path]; ok {
+		return expandValue(v), nil
+	}
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return rm.find(steps), nil
+}
+
+// find returns all values selected by steps
+func (rm ResultMap) find(steps []pathStep) []interface{} {
+	nodes := []interface{}{rm}
+	for _, st := range steps {
+		var next []interface{}
+		for _, n := range nodes {
+			if m, ok := n.(ResultMap); ok {
+				next = append(next, st.apply(m)...)
+			}
+		}
+		if nodes = next; len(nodes) == 0 {
+			break
+		}
+	}
+	return nodes
+}
+
+// value returns the value at path without expanding repeated elements,
+// so a []string or []ResultMap is returned whole.  When the last step has
+// a predicate or is a wildcard, the first matching value is returned.
+func (rm ResultMap) value(path string) (interface{}, error) {
+	if v, ok := rm[path]; ok {
+		return v, nil
+	}
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	last := steps[len(steps)-1]
+	for _, n := range rm.find(steps[:len(steps)-1]) {
+		m, ok := n.(ResultMap)
+		if !ok {
+			continue
+		}
+		if len(last.preds) == 0 && last.name != "*" {
+			if v, ok := m[last.name]; ok {
+				return v, nil
+			}
+		} else if vals := last.apply(m); len(vals) > 0 {
+			return vals[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// Get returns the first value matching path or nil when no value
+// is found or the path is invalid.
+func (rm ResultMap) Get(path string) interface{} {
+	if vals, _ := rm.Find(path); len(vals) > 0 {
+		return vals[0]
+	}
+	return nil
+}
+
+// apply returns the child values of m selected by the step
+func (st pathStep) apply(m ResultMap) []interface{} {
+	var vals []interface{}
+	if st.name == "*" {
+		for _, k := range m.elementKeys() {
+			vals = append(vals, expandValue(m[k])...)
+		}
+	} else {
+		vals = expandValue(m[st.name])
+	}
+	for _, p := range st.preds {
+		vals = p.filter(vals)
+	}
+	return vals
+}
+
+func (p pathPred) filter(vals []interface{}) []interface{} {
+	if p.isIndex {
+		idx := p.index
+		if idx < 0 {
+			idx += len(vals)
+		}
+		if idx < 0 || idx >= len(vals) {
+			return nil
+		}
+		return vals[idx : idx+1]
+	}
+	var matches []interface{}
+	for _, v := range vals {
+		var s string
+		var ok bool
+		if p.key == "." {
+			s, ok = valueText(v)
+		} else if m, isMap := v.(ResultMap); isMap {
+			s, ok = valueText(m.Get(p.key))
+		}
+		if (p.op == "" && ok) || (p.op == "=" && ok && s == p.value) || (p.op == "!=" && s != p.value) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+// expandValue returns repeated elements as separate values
+func expandValue(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case []ResultMap:
+		vals := make([]interface{}, len(val))
+		for i := range val {
+			vals[i] = val[i]
+		}
+		return vals
+	case []string:
+		vals := make([]interface{}, len(val))
+		for i := range val {
+			vals[i] = val[i]
+		}
+		return vals
+	}
+	return []interface{}{v}
+}
+
+// valueText returns a string value or the character data of an
+// element with attributes.
+func valueText(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case ResultMap:
+		s, ok := val[""].(string)
+		return s, ok
+	}
+	return "", false
+}
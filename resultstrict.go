@@ -0,0 +1,317 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldErrorKind describes why a ResultMap value could not be converted
+type FieldErrorKind int
+
+// FieldError kinds
+const (
+	FieldInvalidPath FieldErrorKind = iota + 1 // path expression is malformed
+	FieldMissing                               // no value found at path
+	FieldWrongKind                             // value is a nested element rather than text
+	FieldParse                                 // text could not be parsed
+)
+
+// FieldError is returned by the strict ResultMap accessors
+type FieldError struct {
+	Path  string
+	Kind  FieldErrorKind
+	Value string // text that failed to parse
+	Err   error  // underlying error for FieldInvalidPath and FieldParse
+}
+
+// Error fulfills the error interface
+func (e *FieldError) Error() string {
+	switch e.Kind {
+	case FieldInvalidPath:
+		return e.Err.Error()
+	case FieldMissing:
+		return e.Path + ": not found"
+	case FieldWrongKind:
+		return e.Path + ": not a text value"
+	}
+	return fmt.Sprintf("%s: unable to parse %q: %v", e.Path, e.Value, e.Err)
+}
+
+// IsFieldMissing returns true when err is a FieldError of kind FieldMissing
+func IsFieldMissing(err error) bool {
+	fe, ok := err.(*FieldError)
+	return ok && fe.Kind == FieldMissing
+}
+
+// FieldErrors lists all conversion errors of a record
+type FieldErrors []*FieldError
+
+// Error fulfills the error interface
+func (fe FieldErrors) Error() string {
+	msgs := make([]string, len(fe))
+	for i, e := range fe {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func parseError(path, val string, err error) *FieldError {
+	if numErr, ok := err.(*strconv.NumError); ok {
+		err = numErr.Err
+	}
+	return &FieldError{Path: path, Kind: FieldParse, Value: val, Err: err}
+}
+
+// StringE returns the text of the first value matching path.  Unlike
+// String, an error is returned when the value is missing or is a nested
+// element.
+func (rm ResultMap) StringE(path string) (string, error) {
+	vals, err := rm.Find(path)
+	if err != nil {
+		return "", &FieldError{Path: path, Kind: FieldInvalidPath, Err: err}
+	}
+	if len(vals) == 0 {
+		return "", &FieldError{Path: path, Kind: FieldMissing}
+	}
+	s, ok := valueText(vals[0])
+	if !ok {
+		return "", &FieldError{Path: path, Kind: FieldWrongKind}
+	}
+	return s, nil
+}
+
+// IntE parses the value at path as an integer
+func (rm ResultMap) IntE(path string) (int64, error) {
+	s, err := rm.StringE(path)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, parseError(path, s, err)
+	}
+	return i, nil
+}
+
+// FloatE parses the value at path as a float64
+func (rm ResultMap) FloatE(path string) (float64, error) {
+	s, err := rm.StringE(path)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, parseError(path, s, err)
+	}
+	return f, nil
+}
+
+// BoolE parses the value at path.  With no trueVals, the value must be
+// one of the variants accepted by ParseBool.  Otherwise the result is true when the value matches
+// one of trueVals.
+func (rm ResultMap) BoolE(path string, trueVals ...string) (bool, error) {
+	s, err := rm.StringE(path)
+	if err != nil {
+		return false, err
+	}
+	if len(trueVals) == 0 {
+		b, err := ParseBool(s)
+		if err != nil {
+			return false, parseError(path, s, strconv.ErrSyntax)
+		}
+		return b, nil
+	}
+	for _, v := range trueVals {
+		if s == v {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DateE returns the date at path.  The value may be an element with
+// Year, Month and Day children or text formatted YYYY-MM-DD, MM-DD-YYYY
+// or MM/DD/YYYY.
+func (rm ResultMap) DateE(path string) (time.Time, error) {
+	vals, err := rm.Find(path)
+	if err != nil {
+		return time.Time{}, &FieldError{Path: path, Kind: FieldInvalidPath, Err: err}
+	}
+	if len(vals) == 0 {
+		return time.Time{}, &FieldError{Path: path, Kind: FieldMissing}
+	}
+	if m, ok := vals[0].(ResultMap); ok {
+		if _, hasText := m[""]; !hasText {
+			return dateFromParts(path, m)
+		}
+	}
+	s, _ := valueText(vals[0])
+	for _, layout := range []string{"2006-01-02", "01-02-2006", "01/02/2006"} {
+		if tm, err := time.Parse(layout, s); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, parseError(path, s, fmt.Errorf("expected YYYY-MM-DD"))
+}
+
+// dateFromParts reads a date from Year, Month and Day elements
+func dateFromParts(path string, m ResultMap) (time.Time, error) {
+	yr, mth, day := -1, -1, -1
+	for k, v := range m {
+		sval, _ := v.(string)
+		intVal, _ := strconv.Atoi(sval)
+		switch k {
+		case "Year", "year":
+			yr = intVal
+		case "Month", "month":
+			mth = intVal
+		case "Day", "day":
+			day = intVal
+		}
+	}
+	if yr > 0 && mth > 0 && day > 0 {
+		return time.Date(yr, time.Month(mth), day, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, &FieldError{Path: path, Kind: FieldWrongKind}
+}
+
+// TimestampE parses an RFC3339 formatted value at path
+func (rm ResultMap) TimestampE(path string) (time.Time, error) {
+	return rm.timeE(path, time.RFC3339, time.UTC)
+}
+
+// DateTimeE parses a MM/DD/YYYY HH:MM:SS formatted value at path as UTC.
+// Use DateTimeInE to read the value in the company's time zone.
+func (rm ResultMap) DateTimeE(path string) (time.Time, error) {
+	return rm.timeE(path, "01/02/2006 15:04:05", time.UTC)
+}
+
+// DateTimeInE parses a MM/DD/YYYY HH:MM:SS formatted value at path in
+// loc, usually Service.Location.
+func (rm ResultMap) DateTimeInE(path string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return rm.timeE(path, "01/02/2006 15:04:05", loc)
+}
+
+func (rm ResultMap) timeE(path, layout string, loc *time.Location) (time.Time, error) {
+	s, err := rm.StringE(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tm, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return time.Time{}, parseError(path, s, fmt.Errorf("expected %s", layout))
+	}
+	return tm, nil
+}
+
+// FieldCollector reads values from a ResultMap recording every conversion
+// error so that a record may be checked with a single call to Err.  Missing
+// values return zero values and are only reported by Require.
+//
+//	fc := rec.Collect()
+//	fc.Require("RECORDNO", "VENDORID")
+//	id := fc.Int("RECORDNO")
+//	due := fc.Float("TOTALDUE")
+//	if err := fc.Err(); err != nil {
+//	    return err
+//	}
+type FieldCollector struct {
+	rm   ResultMap
+	errs FieldErrors
+}
+
+// Collect returns a FieldCollector for rm
+func (rm ResultMap) Collect() *FieldCollector {
+	return &FieldCollector{rm: rm}
+}
+
+// add records err unless it is a missing value
+func (fc *FieldCollector) add(err error) {
+	if fe, ok := err.(*FieldError); ok && fe.Kind != FieldMissing {
+		fc.errs = append(fc.errs, fe)
+	}
+}
+
+// Require records an error for each path without a value
+func (fc *FieldCollector) Require(paths ...string) {
+	for _, p := range paths {
+		if _, err := fc.rm.StringE(p); err != nil {
+			fc.errs = append(fc.errs, err.(*FieldError))
+		}
+	}
+}
+
+// String returns the text value at path
+func (fc *FieldCollector) String(path string) string {
+	s, err := fc.rm.StringE(path)
+	fc.add(err)
+	return s
+}
+
+// Int returns the integer value at path
+func (fc *FieldCollector) Int(path string) int64 {
+	i, err := fc.rm.IntE(path)
+	fc.add(err)
+	return i
+}
+
+// Float returns the float value at path
+func (fc *FieldCollector) Float(path string) float64 {
+	f, err := fc.rm.FloatE(path)
+	fc.add(err)
+	return f
+}
+
+// Bool returns the bool value at path
+func (fc *FieldCollector) Bool(path string, trueVals ...string) bool {
+	b, err := fc.rm.BoolE(path, trueVals...)
+	fc.add(err)
+	return b
+}
+
+// Date returns the date at path, nil if missing or invalid
+func (fc *FieldCollector) Date(path string) *time.Time {
+	return fc.timePtr(fc.rm.DateE(path))
+}
+
+// Timestamp returns the RFC3339 time at path, nil if missing or invalid
+func (fc *FieldCollector) Timestamp(path string) *time.Time {
+	return fc.timePtr(fc.rm.TimestampE(path))
+}
+
+// DateTime returns the MM/DD/YYYY HH:MM:SS time at path, nil if missing
+// or invalid
+func (fc *FieldCollector) DateTime(path string) *time.Time {
+	return fc.timePtr(fc.rm.DateTimeE(path))
+}
+
+func (fc *FieldCollector) timePtr(tm time.Time, err error) *time.Time {
+	if err != nil {
+		fc.add(err)
+		return nil
+	}
+	return &tm
+}
+
+// Errors returns the errors collected so far
+func (fc *FieldCollector) Errors() FieldErrors {
+	return fc.errs
+}
+
+// Err returns a FieldErrors if any errors were collected, otherwise nil
+func (fc *FieldCollector) Err() error {
+	if len(fc.errs) == 0 {
+		return nil
+	}
+	return fc.errs
+}
@@ -0,0 +1,338 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaField merges the Lookup (ObjectField) and Inspect (FieldDetail)
+// descriptions of an object's field.
+type SchemaField struct {
+	ID               string   `json:"id"`
+	Label            string   `json:"label,omitempty"`
+	Description      string   `json:"description,omitempty"`
+	DataType         string   `json:"data_type,omitempty"` // Lookup DATATYPE
+	DataName         string   `json:"data_name,omitempty"` // Inspect dataName (Pt_Field...)
+	Required         bool     `json:"required,omitempty"`
+	ReadOnly         bool     `json:"read_only,omitempty"`
+	IsCustom         bool     `json:"is_custom,omitempty"`
+	ValidValues      []string `json:"valid_values,omitempty"`
+	MaxLen           int      `json:"max_length,omitempty"`
+	GroupName        string   `json:"group_name,omitempty"`
+	ExternalDataName string   `json:"external_data_name,omitempty"`
+	Relationship     string   `json:"relationship,omitempty"`
+	RelatedObject    string   `json:"related_object,omitempty"`
+}
+
+// ObjectField returns the Lookup form of the field
+func (sf SchemaField) ObjectField() ObjectField {
+	dataType := sf.DataType
+	if dataType == "" {
+		dataType = sf.DataName
+	}
+	return ObjectField{
+		ID:          sf.ID,
+		Label:       sf.Label,
+		Description: sf.Description,
+		Required:    sf.Required,
+		ReadOnly:    sf.ReadOnly,
+		DataType:    dataType,
+		IsCustom:    sf.IsCustom,
+		ValidValues: sf.ValidValues,
+	}
+}
+
+// Schema describes an intacct object by combining the results of the
+// Lookup and Inspect functions.
+type Schema struct {
+	Name          string               `json:"name"`
+	DocumentType  string               `json:"document_type,omitempty"`
+	SingularName  string               `json:"singular_name,omitempty"`
+	PluralName    string               `json:"plural_name,omitempty"`
+	Description   string               `json:"description,omitempty"`
+	Fields        []SchemaField        `json:"fields"`
+	Relationships []ObjectRelationship `json:"relationships,omitempty"`
+	Fetched       time.Time            `json:"fetched"`
+}
+
+// NewSchema merges lookup and inspect definitions of an object.  Either
+// parameter may be nil.  Fields are listed in lookup order followed by fields
+// found only in the inspect definition.
+func NewSchema(ot *ObjectType, detail *InspectDetailResult) *Schema {
+	s := &Schema{Fetched: time.Now()}
+	var idx = make(map[string]int)
+	if ot != nil {
+		s.Name, s.DocumentType, s.Relationships = ot.Name, ot.Type, ot.Relationships
+		for _, f := range ot.Fields {
+			idx[strings.ToUpper(f.ID)] = len(s.Fields)
+			s.Fields = append(s.Fields, SchemaField{
+				ID:          f.ID,
+				Label:       f.Label,
+				Description: f.Description,
+				DataType:    f.DataType,
+				Required:    f.Required,
+				ReadOnly:    f.ReadOnly,
+				IsCustom:    f.IsCustom,
+				ValidValues: f.ValidValues,
+			})
+		}
+	}
+	if detail == nil {
+		return s
+	}
+	if s.Name == "" {
+		s.Name = detail.Name
+	}
+	s.SingularName, s.PluralName, s.Description = detail.SingularName, detail.PluralName, detail.Description
+	for _, fd := range detail.Fields {
+		i, ok := idx[strings.ToUpper(fd.Name)]
+		if !ok {
+			i = len(s.Fields)
+			idx[strings.ToUpper(fd.Name)] = i
+			s.Fields = append(s.Fields, SchemaField{
+				ID:       fd.Name,
+				Label:    fd.DisplayLabel,
+				Required: fd.IsRequired,
+				ReadOnly: fd.IsReadOnly,
+			})
+		}
+		sf := &s.Fields[i]
+		sf.DataName = fd.DataName
+		sf.MaxLen, _ = strconv.Atoi(fd.MaxLen)
+		sf.GroupName = fd.GroupName
+		sf.ExternalDataName = fd.ExternalDataName
+		sf.Relationship = fd.Relationship
+		sf.RelatedObject = fd.RelatedObject
+		if sf.Label == "" {
+			sf.Label = fd.DisplayLabel
+		}
+		if sf.Description == "" {
+			sf.Description = fd.Description
+		}
+	}
+	return s
+}
+
+// Field returns the named field.  Names are not case sensitive.
+func (s *Schema) Field(id string) (SchemaField, bool) {
+	if s != nil {
+		for _, f := range s.Fields {
+			if strings.EqualFold(f.ID, id) {
+				return f, true
+			}
+		}
+	}
+	return SchemaField{}, false
+}
+
+// CustomFields lists fields flagged as custom by the Lookup function.
+func (s *Schema) CustomFields() []SchemaField {
+	var flds []SchemaField
+	if s != nil {
+		for _, f := range s.Fields {
+			if f.IsCustom {
+				flds = append(flds, f)
+			}
+		}
+	}
+	return flds
+}
+
+// Relationship returns the relationship whose path or related object
+// name matches nm.
+func (s *Schema) Relationship(nm string) (ObjectRelationship, bool) {
+	if s != nil {
+		for _, r := range s.Relationships {
+			if strings.EqualFold(r.Path, nm) || strings.EqualFold(r.Name, nm) {
+				return r, true
+			}
+		}
+	}
+	return ObjectRelationship{}, false
+}
+
+// ObjectType returns the schema in Lookup form for use with functions
+// such as ObjectType.ValidateQuery.
+func (s *Schema) ObjectType() *ObjectType {
+	if s == nil {
+		return nil
+	}
+	ot := &ObjectType{
+		Name:          s.Name,
+		Type:          s.DocumentType,
+		Fields:        make([]ObjectField, 0, len(s.Fields)),
+		Relationships: s.Relationships,
+	}
+	for _, f := range s.Fields {
+		ot.Fields = append(ot.Fields, f.ObjectField())
+	}
+	return ot
+}
+
+// SchemaRegistry lazily fetches and caches object definitions.  A registry
+// is safe for concurrent use.
+type SchemaRegistry struct {
+	// Service used to fetch definitions. If nil, the registry only returns
+	// schemas added by Put or loaded from json.
+	Service *Service
+	// TTL is the maximum age of a cached schema.  Zero means schemas never expire.
+	TTL     time.Duration
+	m       sync.Mutex
+	schemas map[string]*Schema
+}
+
+// NewSchemaRegistry returns a registry using sv to fetch definitions.
+func NewSchemaRegistry(sv *Service, ttl time.Duration) *SchemaRegistry {
+	return &SchemaRegistry{
+		Service: sv,
+		TTL:     ttl,
+		schemas: make(map[string]*Schema),
+	}
+}
+
+func (r *SchemaRegistry) cached(key string) (*Schema, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	s, ok := r.schemas[key]
+	if !ok {
+		return nil, false
+	}
+	return s, r.Service == nil || r.TTL <= 0 || time.Since(s.Fetched) < r.TTL
+}
+
+// Get returns the schema of the named object, fetching the definition
+// when not cached or expired.
+func (r *SchemaRegistry) Get(ctx context.Context, objectName string) (*Schema, error) {
+	key := strings.ToUpper(objectName)
+	s, isCurrent := r.cached(key)
+	if isCurrent {
+		return s, nil
+	}
+	if r.Service == nil {
+		return nil, errors.New("schema not found for " + objectName)
+	}
+	s, err := FetchSchema(ctx, r.Service, objectName)
+	if err != nil {
+		return nil, err
+	}
+	r.Put(s)
+	return s, nil
+}
+
+// ObjectType returns the Lookup form of the named object's schema.
+func (r *SchemaRegistry) ObjectType(ctx context.Context, objectName string) (*ObjectType, error) {
+	s, err := r.Get(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	return s.ObjectType(), nil
+}
+
+// Put adds or replaces schemas in the registry
+func (r *SchemaRegistry) Put(schemas ...*Schema) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.schemas == nil {
+		r.schemas = make(map[string]*Schema)
+	}
+	for _, s := range schemas {
+		if s != nil {
+			r.schemas[strings.ToUpper(s.Name)] = s
+		}
+	}
+}
+
+// Invalidate removes the named objects from the cache.  If no
+// names are passed, all schemas are removed.
+func (r *SchemaRegistry) Invalidate(objectNames ...string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if len(objectNames) == 0 {
+		r.schemas = make(map[string]*Schema)
+		return
+	}
+	for _, nm := range objectNames {
+		delete(r.schemas, strings.ToUpper(nm))
+	}
+}
+
+// Schemas returns all cached schemas sorted by name.
+func (r *SchemaRegistry) Schemas() []*Schema {
+	r.m.Lock()
+	defer r.m.Unlock()
+	list := make([]*Schema, 0, len(r.schemas))
+	for _, s := range r.schemas {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// WriteJSON serializes all cached schemas as a json array.
+func (r *SchemaRegistry) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.Schemas())
+}
+
+// ReadJSON adds schemas serialized by WriteJSON to the registry.
+func (r *SchemaRegistry) ReadJSON(rdr io.Reader) error {
+	var schemas []*Schema
+	if err := json.NewDecoder(rdr).Decode(&schemas); err != nil {
+		return err
+	}
+	r.Put(schemas...)
+	return nil
+}
+
+// SaveFile writes the cached schemas to the named file.
+func (r *SchemaRegistry) SaveFile(fn string) error {
+	buff := &bytes.Buffer{}
+	if err := r.WriteJSON(buff); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fn, buff.Bytes(), 0644)
+}
+
+// LoadFile adds schemas from a file created by SaveFile.
+func (r *SchemaRegistry) LoadFile(fn string) error {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+	return r.ReadJSON(bytes.NewReader(b))
+}
+
+// FetchSchema calls the Lookup and Inspect functions for the named object
+// and merges the results.
+func FetchSchema(ctx context.Context, sv *Service, objectName string) (*Schema, error) {
+	resp, err := sv.Exec(ctx, Lookup{ObjectName: objectName}, ObjectFields(objectName, true))
+	if err != nil {
+		return nil, err
+	}
+	var ot *ObjectType
+	var detail *InspectDetailResult
+	if err = resp.Decode(&ot, &detail); err != nil {
+		return nil, err
+	}
+	if ot == nil && detail == nil {
+		return nil, errors.New("no definition returned for " + objectName)
+	}
+	return NewSchema(ot, detail), nil
+}
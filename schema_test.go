@@ -0,0 +1,109 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+	"github.com/jfcote87/testutils"
+)
+
+const vendorInspect = `<Type Name="VENDOR">
+	<Attributes><SingularName>Vendor</SingularName><PluralName>Vendors</PluralName><Description>Vendor list</Description></Attributes>
+	<Fields>
+		<Field><Name>RECORDNO</Name><dataName>Pt_FieldInt</dataName><isRequired>false</isRequired><isReadOnly>true</isReadOnly><maxLength>8</maxLength><DisplayLabel>Record number</DisplayLabel><Description>Record number</Description></Field>
+		<Field><Name>NAME</Name><dataName>Pt_FieldString</dataName><isRequired>true</isRequired><isReadOnly>false</isReadOnly><maxLength>100</maxLength><DisplayLabel>Vendor name</DisplayLabel><Description>Name of vendor</Description></Field>
+		<Field><Name>DISPLAYCONTACT.CONTACTNAME</Name><dataName>Pt_FieldString</dataName><isRequired>false</isRequired><isReadOnly>false</isReadOnly><maxLength>200</maxLength><DisplayLabel>Contact name</DisplayLabel><relationship>contact</relationship><relatedObject>CONTACT</relatedObject></Field>
+	</Fields>
+</Type>`
+
+func schemaResponse() []byte {
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<response>
+	<control><status>success</status><senderid>SENDERID</senderid><controlid>1</controlid><uniqueid>false</uniqueid><dtdversion>3.0</dtdversion></control>
+	<operation>
+		<authentication><status>success</status><userid>xml_gateway</userid><companyid>Company</companyid></authentication>
+		<result><status>success</status><function>lookup</function><controlid>1</controlid><data listtype="All" count="1">` +
+		vendorLookup + `</data></result>
+		<result><status>success</status><function>inspect</function><controlid>1</controlid><data listtype="All" count="1">` +
+		vendorInspect + `</data></result>
+	</operation>
+</response>`)
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	ctx := context.Background()
+	testTransport := &testutils.Transport{}
+	for i := 0; i < 2; i++ {
+		testTransport.Add(&testutils.RequestTester{
+			Method:   "POST",
+			Response: testutils.MakeResponse(200, schemaResponse(), xmlHeader),
+		})
+	}
+	sv := &intacct.Service{
+		SenderID:      "SENDERID",
+		Password:      "*******",
+		Authenticator: intacct.SessionID("SESSIONID"),
+		HTTPClientFunc: func(ctx context.Context) (*http.Client, error) {
+			return &http.Client{Transport: testTransport}, nil
+		},
+	}
+	reg := intacct.NewSchemaRegistry(sv, time.Hour)
+	s, err := reg.Get(ctx, "vendor")
+	if err != nil {
+		t.Fatalf("registry get: %v", err)
+	}
+	if s.Name != "VENDOR" || s.SingularName != "Vendor" || len(s.Fields) != 10 {
+		t.Fatalf("expected VENDOR schema with 10 fields; got %s %s %d", s.Name, s.SingularName, len(s.Fields))
+	}
+	nm, _ := s.Field("NAME")
+	if nm.DataType != "TEXT" || nm.DataName != "Pt_FieldString" || nm.MaxLen != 100 || !nm.Required || nm.Description != "Name of vendor" {
+		t.Errorf("expected merged NAME field; got %#v", nm)
+	}
+	if dc, ok := s.Field("DISPLAYCONTACT.CONTACTNAME"); !ok || dc.RelatedObject != "CONTACT" || dc.DataType != "" {
+		t.Errorf("expected inspect only field DISPLAYCONTACT.CONTACTNAME; got %#v", dc)
+	}
+	if cf := s.CustomFields(); len(cf) != 1 || cf[0].ID != "REGION" {
+		t.Errorf("expected custom field REGION; got %v", cf)
+	}
+	if r, ok := s.Relationship("term"); !ok || r.Name != "APTERM" {
+		t.Errorf("expected TERM relationship; got %#v", r)
+	}
+	// second call must use cache (transport has a single response remaining)
+	if s2, err := reg.Get(ctx, "VENDOR"); err != nil || s2 != s {
+		t.Fatalf("expected cached schema; got %v", err)
+	}
+
+	buff := &bytes.Buffer{}
+	if err = reg.WriteJSON(buff); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	offline := &intacct.SchemaRegistry{}
+	if err = offline.ReadJSON(buff); err != nil {
+		t.Fatalf("read json: %v", err)
+	}
+	ot, err := offline.ObjectType(ctx, "VENDOR")
+	if err != nil {
+		t.Fatalf("offline registry: %v", err)
+	}
+	if errs := ot.ValidateQuery(intacct.Query{Object: "VENDOR", Select: intacct.Select{Fields: []string{"NAME", "DISPLAYCONTACT.CONTACTNAME"}}}); errs != nil {
+		t.Errorf("expected valid query; got %v", errs)
+	}
+	if _, err = offline.Get(ctx, "CUSTOMER"); err == nil {
+		t.Errorf("expected error for uncached object in offline registry")
+	}
+
+	// expire cache and refetch
+	s.Fetched = time.Now().Add(-2 * time.Hour)
+	if s2, err := reg.Get(ctx, "VENDOR"); err != nil || s2 == s {
+		t.Errorf("expected refreshed schema; got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SchemaChangeKind identifies the type of a SchemaChange
+type SchemaChangeKind string
+
+// Kinds of schema changes
+const (
+	ObjectAdded         SchemaChangeKind = "object_added"
+	ObjectRemoved       SchemaChangeKind = "object_removed"
+	FieldAdded          SchemaChangeKind = "field_added"
+	FieldRemoved        SchemaChangeKind = "field_removed"
+	TypeChanged         SchemaChangeKind = "type_changed"
+	RequiredChanged     SchemaChangeKind = "required_changed"
+	ReadOnlyChanged     SchemaChangeKind = "read_only_changed"
+	MaxLenChanged       SchemaChangeKind = "max_length_changed"
+	ValidValuesChanged  SchemaChangeKind = "valid_values_changed"
+	RelationshipAdded   SchemaChangeKind = "relationship_added"
+	RelationshipRemoved SchemaChangeKind = "relationship_removed"
+	RelationshipChanged SchemaChangeKind = "relationship_changed"
+)
+
+// SchemaChange describes a difference between two versions of an object's
+// schema.  A change is breaking when code written against the old version
+// may fail against the new one (e.g. a removed field or a field that
+// becomes required).
+type SchemaChange struct {
+	Object       string           `json:"object"`
+	Field        string           `json:"field,omitempty"`
+	Relationship string           `json:"relationship,omitempty"`
+	Kind         SchemaChangeKind `json:"kind"`
+	Old          string           `json:"old,omitempty"`
+	New          string           `json:"new,omitempty"`
+	Breaking     bool             `json:"breaking,omitempty"`
+}
+
+// String describes the change on a single line
+func (c SchemaChange) String() string {
+	nm, kind := c.Object, strings.Replace(string(c.Kind), "_", " ", -1)
+	switch {
+	case c.Field > "":
+		nm += "." + c.Field
+	case c.Relationship > "":
+		nm += " relationship " + c.Relationship
+		kind = strings.TrimPrefix(kind, "relationship ")
+	}
+	s := fmt.Sprintf("%s: %s", nm, kind)
+	switch {
+	case c.Old > "" && c.New > "":
+		s += fmt.Sprintf(" (%s -> %s)", c.Old, c.New)
+	case c.Old > "":
+		s += fmt.Sprintf(" (was %s)", c.Old)
+	case c.New > "":
+		s += fmt.Sprintf(" (%s)", c.New)
+	}
+	if c.Breaking {
+		s = "BREAKING " + s
+	}
+	return s
+}
+
+// SchemaDiff lists the changes between two sets of schemas in object
+// name order.
+type SchemaDiff []SchemaChange
+
+// Breaking returns true if any change is breaking
+func (d SchemaDiff) Breaking() bool {
+	for _, c := range d {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes each change on a line followed by a summary
+func (d SchemaDiff) WriteText(w io.Writer) error {
+	var breaking int
+	for _, c := range d {
+		if c.Breaking {
+			breaking++
+		}
+		if _, err := fmt.Fprintln(w, c.String()); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d changes, %d breaking\n", len(d), breaking)
+	return err
+}
+
+// DiffSchemas compares two sets of schemas, such as snapshots of a sandbox
+// and a production company, matching objects by name.
+func DiffSchemas(from, to []*Schema) SchemaDiff {
+	fromMap, toMap := schemaMap(from), schemaMap(to)
+	var names []string
+	for k := range fromMap {
+		names = append(names, k)
+	}
+	for k := range toMap {
+		if _, ok := fromMap[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	var d SchemaDiff
+	for _, k := range names {
+		f, t := fromMap[k], toMap[k]
+		switch {
+		case t == nil:
+			d = append(d, SchemaChange{Object: f.Name, Kind: ObjectRemoved, Breaking: true})
+		case f == nil:
+			d = append(d, SchemaChange{Object: t.Name, Kind: ObjectAdded})
+		default:
+			d = append(d, f.Diff(t)...)
+		}
+	}
+	return d
+}
+
+func schemaMap(list []*Schema) map[string]*Schema {
+	m := make(map[string]*Schema)
+	for _, s := range list {
+		if s != nil {
+			m[strings.ToUpper(s.Name)] = s
+		}
+	}
+	return m
+}
+
+// Diff returns the changes of fields and relationships from s to the
+// schema to.  Removed fields are listed first in s order followed by added
+// fields in to order.
+func (s *Schema) Diff(to *Schema) SchemaDiff {
+	var d SchemaDiff
+	for _, f := range s.Fields {
+		tf, ok := to.Field(f.ID)
+		if !ok {
+			d = append(d, SchemaChange{Object: s.Name, Field: f.ID, Kind: FieldRemoved, Breaking: true})
+			continue
+		}
+		d = append(d, diffField(s.Name, f, tf)...)
+	}
+	for _, f := range to.Fields {
+		if _, ok := s.Field(f.ID); !ok {
+			d = append(d, SchemaChange{Object: s.Name, Field: f.ID, Kind: FieldAdded, Breaking: f.Required && !f.ReadOnly})
+		}
+	}
+	return append(d, diffRelationships(s.Name, s.Relationships, to.Relationships)...)
+}
+
+func diffField(objName string, f, t SchemaField) SchemaDiff {
+	var d SchemaDiff
+	add := func(kind SchemaChangeKind, old, new string, breaking bool) {
+		d = append(d, SchemaChange{Object: objName, Field: f.ID, Kind: kind, Old: old, New: new, Breaking: breaking})
+	}
+	switch {
+	case f.DataType > "" && t.DataType > "" && !strings.EqualFold(f.DataType, t.DataType):
+		add(TypeChanged, f.DataType, t.DataType, true)
+	case f.DataName > "" && t.DataName > "" && f.DataName != t.DataName:
+		add(TypeChanged, f.DataName, t.DataName, true)
+	}
+	if f.Required != t.Required {
+		add(RequiredChanged, fmt.Sprint(f.Required), fmt.Sprint(t.Required), t.Required && !t.ReadOnly)
+	}
+	if f.ReadOnly != t.ReadOnly {
+		add(ReadOnlyChanged, fmt.Sprint(f.ReadOnly), fmt.Sprint(t.ReadOnly), t.ReadOnly)
+	}
+	if f.MaxLen > 0 && t.MaxLen > 0 && f.MaxLen != t.MaxLen {
+		add(MaxLenChanged, fmt.Sprint(f.MaxLen), fmt.Sprint(t.MaxLen), t.MaxLen < f.MaxLen)
+	}
+	if !sameStrings(f.ValidValues, t.ValidValues) {
+		// restricting a free field or removing a value may reject existing data
+		breaking := len(t.ValidValues) > 0 && (len(f.ValidValues) == 0 || !containsAll(t.ValidValues, f.ValidValues))
+		add(ValidValuesChanged, strings.Join(f.ValidValues, ", "), strings.Join(t.ValidValues, ", "), breaking)
+	}
+	return d
+}
+
+func diffRelationships(objName string, from, to []ObjectRelationship) SchemaDiff {
+	var d SchemaDiff
+	toMap := make(map[string]ObjectRelationship)
+	for _, r := range to {
+		toMap[relationshipKey(r)] = r
+	}
+	fromMap := make(map[string]bool)
+	for _, r := range from {
+		key := relationshipKey(r)
+		fromMap[key] = true
+		tr, ok := toMap[key]
+		switch {
+		case !ok:
+			d = append(d, SchemaChange{Object: objName, Relationship: key, Kind: RelationshipRemoved, Old: relationshipText(r), Breaking: true})
+		case relationshipText(r) != relationshipText(tr):
+			d = append(d, SchemaChange{Object: objName, Relationship: key, Kind: RelationshipChanged, Old: relationshipText(r), New: relationshipText(tr), Breaking: true})
+		}
+	}
+	for _, r := range to {
+		if key := relationshipKey(r); !fromMap[key] {
+			d = append(d, SchemaChange{Object: objName, Relationship: key, Kind: RelationshipAdded, New: relationshipText(r)})
+		}
+	}
+	return d
+}
+
+func relationshipKey(r ObjectRelationship) string {
+	if r.Path > "" {
+		return strings.ToUpper(r.Path)
+	}
+	return strings.ToUpper(r.Name)
+}
+
+func relationshipText(r ObjectRelationship) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Name, r.Type, r.RelatedBy))
+}
+
+// sameStrings returns true if a and b contain the same values in any order
+func sameStrings(a, b []string) bool {
+	return len(a) == len(b) && containsAll(a, b) && containsAll(b, a)
+}
+
+// containsAll returns true if list contains each value of vals
+func containsAll(list, vals []string) bool {
+	for _, v := range vals {
+		if !containsString(list, v) {
+			return false
+		}
+	}
+	return true
+}
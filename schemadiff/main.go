@@ -0,0 +1,150 @@
+// schemadiff saves snapshots of a company's object definitions and lists
+// the changes between two snapshots.
+//
+//	schemadiff -cfg config.json -snapshot sandbox.json [OBJECTNAME...]
+//	schemadiff [-json] sandbox.json production.json [OBJECTNAME...]
+//
+// The exit status is 2 when breaking changes are found and 1 on error.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/jfcote87/intacct"
+)
+
+var configFile = flag.String("cfg", "", "file name of a json or xml file containing the service definition")
+var snapshotFlag = flag.String("snapshot", "", "file name of the snapshot written from the service")
+var jsonFlag = flag.Bool("json", false, "write changes as json")
+
+const usageMsg = "usage: -cfg [SERVICE_DEF_FILE] -snapshot FILE [OBJECTNAME...] | [-json] OLD_SNAPSHOT NEW_SNAPSHOT [OBJECTNAME...]"
+
+func main() {
+	flag.Parse()
+	var err error
+	var breaking bool
+	switch {
+	case *configFile > "" && *snapshotFlag > "":
+		var sv *intacct.Service
+		if sv, err = getService(*configFile); err != nil {
+			err = fmt.Errorf("error parsing %s: %v", *configFile, err)
+			break
+		}
+		err = snapshot(context.Background(), sv, *snapshotFlag, os.Stderr, flag.Args()...)
+	case *configFile == "" && *snapshotFlag == "" && flag.NArg() >= 2:
+		breaking, err = diff(os.Stdout, flag.Arg(0), flag.Arg(1), *jsonFlag, flag.Args()[2:]...)
+	default:
+		fmt.Fprintf(os.Stdout, "%s\n", usageMsg)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if breaking {
+		os.Exit(2)
+	}
+}
+
+// snapshot fetches the schemas of the named objects, or all objects when
+// no names are passed, and saves them to fn.  Objects whose definitions
+// cannot be fetched are reported to logw and skipped.
+func snapshot(ctx context.Context, sv *intacct.Service, fn string, logw io.Writer, names ...string) error {
+	var err error
+	if len(names) == 0 {
+		if names, err = objectNames(ctx, sv); err != nil {
+			return err
+		}
+	}
+	reg := intacct.NewSchemaRegistry(sv, 0)
+	for _, nm := range names {
+		if _, err = reg.Get(ctx, nm); err != nil {
+			fmt.Fprintf(logw, "%s: %v\n", nm, err)
+		}
+	}
+	if len(reg.Schemas()) == 0 {
+		return fmt.Errorf("no object definitions fetched")
+	}
+	return reg.SaveFile(fn)
+}
+
+// objectNames lists all objects of the company
+func objectNames(ctx context.Context, sv *intacct.Service) ([]string, error) {
+	resp, err := sv.Exec(ctx, &intacct.Inspector{Object: "*"})
+	if err != nil {
+		return nil, fmt.Errorf("exec error: %v", err)
+	}
+	var results []intacct.InspectName
+	if err = resp.Decode(&results); err != nil {
+		return nil, fmt.Errorf("decode error: %v", err)
+	}
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, r.TypeName)
+	}
+	return names, nil
+}
+
+// diff writes the changes between two snapshots limited to the named
+// objects and reports whether any change is breaking.
+func diff(w io.Writer, oldFn, newFn string, asJSON bool, names ...string) (bool, error) {
+	from, err := loadSnapshot(oldFn, names)
+	if err != nil {
+		return false, err
+	}
+	to, err := loadSnapshot(newFn, names)
+	if err != nil {
+		return false, err
+	}
+	d := intacct.DiffSchemas(from, to)
+	if !asJSON {
+		return d.Breaking(), d.WriteText(w)
+	}
+	if d == nil {
+		d = intacct.SchemaDiff{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return d.Breaking(), enc.Encode(struct {
+		Breaking bool               `json:"breaking"`
+		Changes  intacct.SchemaDiff `json:"changes"`
+	}{d.Breaking(), d})
+}
+
+// loadSnapshot returns the schemas saved in fn.  When names are passed,
+// only those objects are returned.
+func loadSnapshot(fn string, names []string) ([]*intacct.Schema, error) {
+	var reg intacct.SchemaRegistry
+	if err := reg.LoadFile(fn); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn, err)
+	}
+	if len(names) == 0 {
+		return reg.Schemas(), nil
+	}
+	var list []*intacct.Schema
+	for _, s := range reg.Schemas() {
+		for _, nm := range names {
+			if strings.EqualFold(s.Name, nm) {
+				list = append(list, s)
+				break
+			}
+		}
+	}
+	return list, nil
+}
+
+func getService(fn string) (*intacct.Service, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	return intacct.ServiceFromConfigJSON(bytes.NewReader(b))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+	"github.com/jfcote87/testutils"
+)
+
+const (
+	sandboxFile    = "../testfiles/schemadiff/sandbox.json"
+	productionFile = "../testfiles/schemadiff/production.json"
+)
+
+func TestDiff(t *testing.T) {
+	var buf bytes.Buffer
+	breaking, err := diff(&buf, sandboxFile, productionFile, false)
+	if err != nil || breaking {
+		t.Fatalf("expected non-breaking changes; got %v %v", breaking, err)
+	}
+	want := "DIVISION: object added\n" +
+		"VENDOR.REGION: valid values changed (East, West -> East, West, North)\n" +
+		"VENDOR.DIVISION: field added\n" +
+		"3 changes, 0 breaking\n"
+	if buf.String() != want {
+		t.Errorf("expected %s; got %s", want, buf.String())
+	}
+
+	buf.Reset()
+	if breaking, err = diff(&buf, productionFile, sandboxFile, true, "vendor"); err != nil || !breaking {
+		t.Fatalf("expected breaking changes; got %v %v", breaking, err)
+	}
+	var result struct {
+		Breaking bool
+		Changes  intacct.SchemaDiff
+	}
+	if err = json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid json output: %v", err)
+	}
+	if !result.Breaking || len(result.Changes) != 2 || result.Changes[1].Kind != intacct.FieldRemoved || result.Changes[1].Field != "DIVISION" {
+		t.Errorf("unexpected json result %s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err = diff(&buf, sandboxFile, sandboxFile, true); err != nil || !strings.Contains(buf.String(), `"changes": []`) {
+		t.Errorf("expected empty change list; got %s %v", buf.String(), err)
+	}
+	if _, err = diff(&buf, sandboxFile, "notfound.json", false); err == nil {
+		t.Errorf("expected error for missing snapshot")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	lookup, err := ioutil.ReadFile("../testfiles/genobject/vendor.lookup.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inspect, err := ioutil.ReadFile("../testfiles/genobject/vendor.inspect.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := http.Header{"Content-Type": {"application/xml"}}
+	testTransport := &testutils.Transport{}
+	testTransport.Add(
+		&testutils.RequestTester{
+			Method:   "POST",
+			Response: testutils.MakeResponse(200, response(resultXML("inspect", `<type typename="VENDOR">Vendor</type><type typename="BADOBJECT">Bad</type>`)), header),
+		},
+		&testutils.RequestTester{
+			Method:   "POST",
+			Response: testutils.MakeResponse(200, response(resultXML("lookup", string(lookup))+resultXML("inspect", string(inspect))), header),
+		},
+		&testutils.RequestTester{
+			Method:   "POST",
+			Response: testutils.MakeResponse(500, []byte("server error"), nil),
+		},
+	)
+	sv := &intacct.Service{
+		SenderID:      "SENDERID",
+		Password:      "*******",
+		Authenticator: intacct.SessionID("SESSIONID"),
+		HTTPClientFunc: func(ctx context.Context) (*http.Client, error) {
+			return &http.Client{Transport: testTransport}, nil
+		},
+	}
+	dir, err := ioutil.TempDir("", "schemadiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "snapshot.json")
+	var logBuf bytes.Buffer
+	if err = snapshot(context.Background(), sv, fn, &logBuf); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	if !strings.HasPrefix(logBuf.String(), "BADOBJECT: ") {
+		t.Errorf("expected BADOBJECT error to be logged; got %s", logBuf.String())
+	}
+	schemas, err := loadSnapshot(fn, nil)
+	if err != nil {
+		t.Fatalf("load snapshot failed: %v", err)
+	}
+	if len(schemas) != 1 || schemas[0].Name != "VENDOR" || len(schemas[0].Relationships) != 2 {
+		t.Fatalf("expected VENDOR schema in snapshot; got %v", schemas)
+	}
+	if f, ok := schemas[0].Field("VENDORID"); !ok || f.MaxLen != 20 || !f.Required {
+		t.Errorf("expected merged VENDORID field; got %#v", f)
+	}
+}
+
+func resultXML(function, data string) string {
+	return `<result><status>success</status><function>` + function + `</function><controlid>1</controlid><data listtype="All" count="1">` +
+		data + `</data></result>`
+}
+
+func response(results string) []byte {
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<response>
+	<control><status>success</status><senderid>SENDERID</senderid><controlid>1</controlid><uniqueid>false</uniqueid><dtdversion>3.0</dtdversion></control>
+	<operation>
+		<authentication><status>success</status><userid>xml_gateway</userid><companyid>Company</companyid></authentication>
+		` + results + `
+	</operation>
+</response>`)
+}
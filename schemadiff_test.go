@@ -0,0 +1,98 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	sandbox := []*intacct.Schema{
+		{
+			Name: "VENDOR",
+			Fields: []intacct.SchemaField{
+				{ID: "RECORDNO", DataType: "INTEGER", ReadOnly: true},
+				{ID: "NAME", DataType: "TEXT", Required: true, MaxLen: 100},
+				{ID: "STATUS", DataType: "TEXT", ValidValues: []string{"active", "inactive"}},
+				{ID: "CREDITLIMIT", DataType: "DECIMAL"},
+				{ID: "REGION", DataType: "TEXT", IsCustom: true},
+				{ID: "TERMNAME", DataType: "TEXT"},
+			},
+			Relationships: []intacct.ObjectRelationship{
+				{Path: "TERM", Name: "APTERM", Type: "MANY2ONE", RelatedBy: "TERMNAME"},
+				{Path: "APBILL", Name: "APBILL", Type: "ONE2MANY"},
+			},
+		},
+		{Name: "APTERM"},
+	}
+	production := []*intacct.Schema{
+		{
+			Name: "vendor",
+			Fields: []intacct.SchemaField{
+				{ID: "RECORDNO", DataType: "INTEGER", ReadOnly: true},
+				{ID: "NAME", DataType: "TEXT", Required: false, MaxLen: 80},
+				{ID: "STATUS", DataType: "TEXT", ValidValues: []string{"inactive", "active", "on hold"}},
+				{ID: "CREDITLIMIT", DataType: "CURRENCY"},
+				{ID: "TERMNAME", DataType: "TEXT", Required: true},
+				{ID: "DIVISION", DataType: "TEXT", IsCustom: true},
+			},
+			Relationships: []intacct.ObjectRelationship{
+				{Path: "TERM", Name: "APTERM", Type: "MANY2ONE", RelatedBy: "TERMKEY"},
+				{Path: "DIVISION", Name: "DIVISION", Type: "MANY2ONE"},
+			},
+		},
+		{Name: "GLACCOUNT"},
+	}
+	d := intacct.DiffSchemas(sandbox, production)
+	want := []struct {
+		field    string
+		kind     intacct.SchemaChangeKind
+		breaking bool
+	}{
+		{"", intacct.ObjectRemoved, true},
+		{"", intacct.ObjectAdded, false},
+		{"NAME", intacct.RequiredChanged, false},
+		{"NAME", intacct.MaxLenChanged, true},
+		{"STATUS", intacct.ValidValuesChanged, false},
+		{"CREDITLIMIT", intacct.TypeChanged, true},
+		{"REGION", intacct.FieldRemoved, true},
+		{"TERMNAME", intacct.RequiredChanged, true},
+		{"DIVISION", intacct.FieldAdded, false},
+		{"TERM", intacct.RelationshipChanged, true},
+		{"APBILL", intacct.RelationshipRemoved, true},
+		{"DIVISION", intacct.RelationshipAdded, false},
+	}
+	if len(d) != len(want) {
+		t.Fatalf("expected %d changes; got %d %v", len(want), len(d), d)
+	}
+	for i, w := range want {
+		c := d[i]
+		if c.Field+c.Relationship != w.field || c.Kind != w.kind || c.Breaking != w.breaking {
+			t.Errorf("change %d expected %s %s %v; got %s", i, w.field, w.kind, w.breaking, c)
+		}
+	}
+	if !d.Breaking() {
+		t.Errorf("expected breaking diff")
+	}
+	if s := d[3].String(); s != "BREAKING VENDOR.NAME: max length changed (100 -> 80)" {
+		t.Errorf("unexpected change text %s", s)
+	}
+	if s := d[10].String(); s != "BREAKING VENDOR relationship APBILL: removed (was APBILL ONE2MANY)" {
+		t.Errorf("unexpected relationship change text %s", s)
+	}
+
+	var buf bytes.Buffer
+	if err := d.WriteText(&buf); err != nil || !strings.HasSuffix(buf.String(), "12 changes, 7 breaking\n") {
+		t.Errorf("unexpected text output %s %v", buf.String(), err)
+	}
+	if d = intacct.DiffSchemas(sandbox, sandbox); len(d) != 0 || d.Breaking() {
+		t.Errorf("expected no changes comparing identical schemas; got %v", d)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// JSONSchemaVersion is the $schema value of documents returned by
+// Schema.JSONSchema
+const JSONSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema is a JSON Schema document describing an object.  Dotted
+// fields (e.g. DISPLAYCONTACT.EMAIL1) are described as nested objects.
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	MaxLength   int                    `json:"maxLength,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	ReadOnly    bool                   `json:"readOnly,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema document describing the object.  Fields
+// that are required and not read only are listed as required by the object
+// containing them.
+func (s *Schema) JSONSchema() *JSONSchema {
+	js := &JSONSchema{
+		Schema:      JSONSchemaVersion,
+		Title:       s.Name,
+		Description: s.Description,
+		Type:        "object",
+		Properties:  make(map[string]*JSONSchema),
+	}
+	for _, f := range s.Fields {
+		parent, path := js, strings.Split(f.ID, ".")
+		for _, nm := range path[:len(path)-1] {
+			child, ok := parent.Properties[nm]
+			if !ok || child.Properties == nil {
+				child = &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+				parent.Properties[nm] = child
+			}
+			parent = child
+		}
+		if prev, ok := parent.Properties[path[len(path)-1]]; ok && prev.Properties != nil {
+			continue // nested fields take precedence
+		}
+		prop := &JSONSchema{Description: f.Label, ReadOnly: f.ReadOnly}
+		if f.Description > "" && !strings.EqualFold(f.Description, f.Label) {
+			if prop.Description > "" {
+				prop.Description += ": "
+			}
+			prop.Description += f.Description
+		}
+		switch kindOf(f.dataType()) {
+		case kindInteger:
+			prop.Type = "integer"
+		case kindNumber:
+			prop.Type = "number"
+		case kindBool:
+			prop.Type = "boolean"
+		case kindDate:
+			prop.Type, prop.Format = "string", "date"
+		case kindTimestamp:
+			prop.Type, prop.Format = "string", "date-time"
+		default:
+			prop.Type, prop.MaxLength, prop.Enum = "string", f.MaxLen, f.ValidValues
+		}
+		parent.Properties[path[len(path)-1]] = prop
+		if f.Required && !f.ReadOnly {
+			parent.Required = append(parent.Required, path[len(path)-1])
+		}
+	}
+	return js
+}
+
+// dataType returns the Lookup data type or, when blank, the Inspect data name
+func (sf SchemaField) dataType() string {
+	if sf.DataType > "" {
+		return sf.DataType
+	}
+	return sf.DataName
+}
+
+// isCurrency returns true for currency amounts
+func (sf SchemaField) isCurrency() bool {
+	dt := strings.ToUpper(sf.dataType())
+	return dt == "CURRENCY" || dt == "PT_FIELDCURRENCY"
+}
+
+// SQLDialect determines the column types of Schema.CreateTable
+type SQLDialect string
+
+// Supported SQL dialects
+const (
+	PostgreSQL SQLDialect = "postgres"
+	SQLite     SQLDialect = "sqlite"
+)
+
+// sqlTypes lists the column type of each field kind by dialect.  Text
+// fields with a maximum length use varchar in PostgreSQL.
+var sqlTypes = map[SQLDialect]map[fieldKind]string{
+	PostgreSQL: {
+		kindText:      "text",
+		kindInteger:   "bigint",
+		kindNumber:    "numeric",
+		kindDate:      "date",
+		kindTimestamp: "timestamp",
+		kindBool:      "boolean",
+	},
+	SQLite: {
+		kindText:      "TEXT",
+		kindInteger:   "INTEGER",
+		kindNumber:    "NUMERIC",
+		kindDate:      "TEXT",
+		kindTimestamp: "TEXT",
+		kindBool:      "INTEGER",
+	},
+}
+
+// CreateTable returns a CREATE TABLE statement for the object.  Table and
+// column names are lower case with dots replaced by underscores, and
+// RECORDNO is the primary key.  Relationships are written as comments on
+// the column relating the objects or, when there is no such column, after
+// the statement.
+func (s *Schema) CreateTable(dialect SQLDialect) (string, error) {
+	types, ok := sqlTypes[dialect]
+	if !ok {
+		return "", fmt.Errorf("unknown sql dialect %q", dialect)
+	}
+	if len(s.Fields) == 0 {
+		return "", fmt.Errorf("%s has no fields", s.Name)
+	}
+	used := make(map[int]bool)
+	var cols []string
+	var comments []string
+	for _, f := range s.Fields {
+		col := sqlName(f.ID) + " "
+		k := kindOf(f.dataType())
+		switch {
+		case k == kindUnknown:
+			col += types[kindText]
+		case dialect == PostgreSQL && k == kindText && f.MaxLen > 0:
+			col += fmt.Sprintf("varchar(%d)", f.MaxLen)
+		case dialect == PostgreSQL && f.isCurrency():
+			col += "numeric(18,2)"
+		default:
+			col += types[k]
+		}
+		if strings.EqualFold(f.ID, "RECORDNO") {
+			col += " PRIMARY KEY"
+		}
+		var comment string
+		for i, r := range s.Relationships {
+			if strings.EqualFold(r.RelatedBy, f.ID) {
+				used[i] = true
+				comment = fmt.Sprintf(" -- references %s (%s %s)", strings.ToLower(r.Name), r.Path, r.Type)
+				break
+			}
+		}
+		if comment == "" && f.RelatedObject > "" {
+			comment = " -- references " + strings.ToLower(f.RelatedObject)
+		}
+		cols = append(cols, col)
+		comments = append(comments, comment)
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "CREATE TABLE %s (\n", sqlName(s.Name))
+	for i, col := range cols {
+		sep := ","
+		if i == len(cols)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(buf, "    %s%s%s\n", col, sep, comments[i])
+	}
+	buf.WriteString(");\n")
+	for i, r := range s.Relationships {
+		if !used[i] {
+			fmt.Fprintf(buf, "-- relationship %s: %s %s\n", r.Path, r.Type, strings.ToLower(r.Name))
+		}
+	}
+	return buf.String(), nil
+}
+
+// sqlName returns nm as a quoted lower case identifier
+func sqlName(nm string) string {
+	nm = strings.ToLower(strings.Replace(nm, ".", "_", -1))
+	return `"` + strings.Replace(nm, `"`, `""`, -1) + `"`
+}
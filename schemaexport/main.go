@@ -0,0 +1,135 @@
+// schemaexport writes object definitions as JSON Schema documents or
+// CREATE TABLE statements for PostgreSQL and SQLite.  Definitions are
+// fetched from a service or read from snapshots saved by schemadiff or
+// SchemaRegistry.SaveFile.
+//
+//	schemaexport -cfg config.json -format postgres VENDOR APBILL
+//	schemaexport -in schema.json -format jsonschema -out ./schemas
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfcote87/intacct"
+)
+
+var configFile = flag.String("cfg", "", "file name of a json or xml file containing the service definition")
+var inFlag = flag.String("in", "", "comma separated list of snapshot files used instead of a service")
+var formatFlag = flag.String("format", "jsonschema", "output format: jsonschema, postgres or sqlite")
+var outFlag = flag.String("out", "", "directory for one file per object; default writes to stdout")
+
+const usageMsg = "usage: -cfg [SERVICE_DEF_FILE] | -in [FILE,...] [-format jsonschema|postgres|sqlite] [-out DIR] [OBJECTNAME...]"
+
+func main() {
+	flag.Parse()
+	var schemas []*intacct.Schema
+	var err error
+	switch {
+	case *inFlag > "":
+		schemas, err = loadSchemas(strings.Split(*inFlag, ","), flag.Args())
+	case *configFile > "" && flag.NArg() > 0:
+		var sv *intacct.Service
+		if sv, err = getService(*configFile); err != nil {
+			err = fmt.Errorf("error parsing %s: %v", *configFile, err)
+			break
+		}
+		schemas, err = fetchSchemas(context.Background(), sv, flag.Args())
+	default:
+		fmt.Fprintf(os.Stdout, "%s\n", usageMsg)
+		os.Exit(1)
+	}
+	if err == nil {
+		err = writeSchemas(os.Stdout, *outFlag, *formatFlag, schemas)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// fetchSchemas returns the schemas of the named objects from the service
+func fetchSchemas(ctx context.Context, sv *intacct.Service, names []string) ([]*intacct.Schema, error) {
+	reg := intacct.NewSchemaRegistry(sv, 0)
+	for _, nm := range names {
+		if _, err := reg.Get(ctx, nm); err != nil {
+			return nil, fmt.Errorf("%s: %v", nm, err)
+		}
+	}
+	return reg.Schemas(), nil
+}
+
+// loadSchemas returns the schemas of the named objects, or all schemas
+// when no names are passed, from snapshot files.
+func loadSchemas(files, names []string) ([]*intacct.Schema, error) {
+	var reg intacct.SchemaRegistry
+	for _, fn := range files {
+		if err := reg.LoadFile(fn); err != nil {
+			return nil, fmt.Errorf("%s: %v", fn, err)
+		}
+	}
+	if len(names) == 0 {
+		return reg.Schemas(), nil
+	}
+	var list []*intacct.Schema
+	for _, nm := range names {
+		s, err := reg.Get(context.Background(), nm)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// writeSchemas writes each schema in the format to a file in outDir or,
+// when outDir is blank, to stdout.
+func writeSchemas(stdout io.Writer, outDir, format string, schemas []*intacct.Schema) error {
+	if outDir > "" {
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return err
+		}
+	}
+	for _, s := range schemas {
+		b, ext, err := render(s, format)
+		if err != nil {
+			return err
+		}
+		if outDir == "" {
+			if _, err = stdout.Write(append(b, '\n')); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = ioutil.WriteFile(filepath.Join(outDir, strings.ToLower(s.Name)+ext), b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render returns the schema in the format along with its file extension
+func render(s *intacct.Schema, format string) ([]byte, string, error) {
+	if format == "jsonschema" {
+		b, err := json.MarshalIndent(s.JSONSchema(), "", "  ")
+		return append(b, '\n'), ".schema.json", err
+	}
+	ddl, err := s.CreateTable(intacct.SQLDialect(format))
+	return []byte(ddl), ".sql", err
+}
+
+func getService(fn string) (*intacct.Service, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	return intacct.ServiceFromConfigJSON(bytes.NewReader(b))
+}
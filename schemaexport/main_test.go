@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+const snapshotFile = "../testfiles/schemadiff/production.json"
+
+func TestLoadSchemas(t *testing.T) {
+	schemas, err := loadSchemas([]string{snapshotFile}, nil)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(schemas) != 3 || schemas[0].Name != "APTERM" {
+		t.Errorf("expected 3 schemas sorted by name; got %d", len(schemas))
+	}
+	if schemas, err = loadSchemas([]string{snapshotFile}, []string{"vendor"}); err != nil || len(schemas) != 1 || schemas[0].Name != "VENDOR" {
+		t.Errorf("expected VENDOR schema; got %v %v", schemas, err)
+	}
+	if _, err = loadSchemas([]string{snapshotFile}, []string{"NOTFOUND"}); err == nil {
+		t.Errorf("expected error for unknown object")
+	}
+}
+
+func TestWriteSchemas(t *testing.T) {
+	schemas, err := loadSchemas([]string{snapshotFile}, []string{"VENDOR"})
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = writeSchemas(&buf, "", "jsonschema", schemas); err != nil {
+		t.Fatalf("jsonschema: %v", err)
+	}
+	var js intacct.JSONSchema
+	if err = json.Unmarshal(buf.Bytes(), &js); err != nil || js.Title != "VENDOR" || js.Properties["REGION"] == nil {
+		t.Errorf("unexpected json schema %s %v", buf.String(), err)
+	}
+
+	dir, err := ioutil.TempDir("", "schemaexport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = writeSchemas(nil, dir, "sqlite", schemas); err != nil {
+		t.Fatalf("sqlite: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "vendor.sql"))
+	if err != nil || !strings.HasPrefix(string(b), "CREATE TABLE \"vendor\" (\n    \"recordno\" INTEGER PRIMARY KEY,") {
+		t.Errorf("unexpected ddl %s %v", b, err)
+	}
+	if err = writeSchemas(&buf, "", "oracle", schemas); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
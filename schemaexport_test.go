@@ -0,0 +1,115 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jfcote87/intacct"
+)
+
+var exportSchema = &intacct.Schema{
+	Name:        "VENDOR",
+	Description: "Vendor list",
+	Fields: []intacct.SchemaField{
+		{ID: "RECORDNO", Label: "Record number", DataType: "INTEGER", ReadOnly: true},
+		{ID: "NAME", Label: "Vendor name", Description: "Name of vendor", DataType: "TEXT", Required: true, MaxLen: 100},
+		{ID: "STATUS", Label: "Status", DataType: "TEXT", ValidValues: []string{"active", "inactive"}},
+		{ID: "ONHOLD", Label: "On hold", DataType: "BOOLEAN"},
+		{ID: "TOTALDUE", Label: "Total due", DataType: "CURRENCY", ReadOnly: true},
+		{ID: "LASTPAID", Label: "Last paid", DataName: "Pt_FieldDate"},
+		{ID: "WHENMODIFIED", Label: "When modified", DataType: "TIMESTAMP", ReadOnly: true},
+		{ID: "TERMNAME", Label: "Term", DataType: "TEXT", MaxLen: 40},
+		{ID: "DISPLAYCONTACT.CONTACTNAME", Label: "Contact name", DataName: "Pt_FieldString", RelatedObject: "CONTACT"},
+	},
+	Relationships: []intacct.ObjectRelationship{
+		{Path: "TERM", Name: "APTERM", Type: "MANY2ONE", RelatedBy: "TERMNAME"},
+		{Path: "APBILL", Name: "APBILL", Type: "ONE2MANY"},
+	},
+}
+
+func TestSchemaJSONSchema(t *testing.T) {
+	s := *exportSchema
+	s.Fields = append(append([]intacct.SchemaField{}, s.Fields...),
+		intacct.SchemaField{ID: "DISPLAYCONTACT.MAILADDRESS.ZIP", Label: "Zip code", DataName: "Pt_FieldString", Required: true})
+	js := s.JSONSchema()
+	if js.Schema != intacct.JSONSchemaVersion || js.Title != "VENDOR" || js.Type != "object" {
+		t.Errorf("unexpected document header %#v", js)
+	}
+	if len(js.Required) != 1 || js.Required[0] != "NAME" {
+		t.Errorf("expected NAME required; got %v", js.Required)
+	}
+	for nm, want := range map[string]string{
+		"RECORDNO":     "integer",
+		"STATUS":       "string",
+		"ONHOLD":       "boolean",
+		"TOTALDUE":     "number",
+		"LASTPAID":     "string date",
+		"WHENMODIFIED": "string date-time",
+	} {
+		p, ok := js.Properties[nm]
+		if !ok || strings.TrimSpace(p.Type+" "+p.Format) != want {
+			t.Errorf("%s expected %s; got %#v", nm, want, p)
+		}
+	}
+	if p := js.Properties["NAME"]; p.MaxLength != 100 || p.Description != "Vendor name: Name of vendor" {
+		t.Errorf("unexpected NAME property %#v", p)
+	}
+	if p := js.Properties["STATUS"]; len(p.Enum) != 2 {
+		t.Errorf("expected STATUS enum; got %#v", p)
+	}
+	if !js.Properties["RECORDNO"].ReadOnly {
+		t.Errorf("expected RECORDNO read only")
+	}
+	dc := js.Properties["DISPLAYCONTACT"]
+	if dc == nil || dc.Type != "object" || dc.Properties["CONTACTNAME"] == nil {
+		t.Fatalf("expected nested DISPLAYCONTACT object; got %#v", dc)
+	}
+	if ma := dc.Properties["MAILADDRESS"]; ma == nil || len(ma.Required) != 1 || ma.Required[0] != "ZIP" || len(dc.Required) != 0 {
+		t.Errorf("expected ZIP required by MAILADDRESS; got %#v", ma)
+	}
+	b, err := json.Marshal(js)
+	if err != nil || !strings.HasPrefix(string(b), `{"$schema":"http://json-schema.org/draft-07/schema#","title":"VENDOR"`) {
+		t.Errorf("unexpected json %s %v", b, err)
+	}
+}
+
+func TestSchemaCreateTable(t *testing.T) {
+	ddl, err := exportSchema.CreateTable(intacct.PostgreSQL)
+	if err != nil {
+		t.Fatalf("postgres: %v", err)
+	}
+	want := `CREATE TABLE "vendor" (
+    "recordno" bigint PRIMARY KEY,
+    "name" varchar(100),
+    "status" text,
+    "onhold" boolean,
+    "totaldue" numeric(18,2),
+    "lastpaid" date,
+    "whenmodified" timestamp,
+    "termname" varchar(40), -- references apterm (TERM MANY2ONE)
+    "displaycontact_contactname" text -- references contact
+);
+-- relationship APBILL: ONE2MANY apbill
+`
+	if ddl != want {
+		t.Errorf("expected %s; got %s", want, ddl)
+	}
+
+	if ddl, err = exportSchema.CreateTable(intacct.SQLite); err != nil {
+		t.Fatalf("sqlite: %v", err)
+	}
+	for _, col := range []string{`"recordno" INTEGER PRIMARY KEY,`, `"name" TEXT,`, `"totaldue" NUMERIC,`, `"onhold" INTEGER,`, `"lastpaid" TEXT,`} {
+		if !strings.Contains(ddl, col) {
+			t.Errorf("sqlite ddl missing %s; got %s", col, ddl)
+		}
+	}
+	if _, err = exportSchema.CreateTable("oracle"); err == nil {
+		t.Errorf("expected error for unknown dialect")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// intacct returns MM/DD/YYYY HH:MM:SS datetimes in the company's (or
+// user's) time zone without an offset.  Set Service.TimeZone and pass
+// Service.Location to the functions below to interpret and format these
+// values.
+
+// Location returns the service's time zone or UTC when not set
+func (sv *Service) Location() *time.Location {
+	if sv == nil || sv.TimeZone == nil {
+		return time.UTC
+	}
+	return sv.TimeZone
+}
+
+// ParseTime parses an intacct MM/DD/YYYY HH:MM:SS or MM/DD/YYYY string in
+// loc.  A nil loc is UTC.
+func ParseTime(s string, loc *time.Location) (time.Time, error) {
+	layout := "01/02/2006 15:04:05"
+	if len(s) == 10 {
+		layout = "01/02/2006"
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.ParseInLocation(layout, s, loc)
+}
+
+// FormatTime formats t in loc as MM/DD/YYYY HH:MM:SS for use as a filter
+// value.  A nil loc is UTC.
+func FormatTime(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("01/02/2006 15:04:05")
+}
+
+// TimeZoneFromResponse returns a fixed time zone using the offset of the
+// response's session timestamp which intacct reports in the time zone of
+// the user's preferences.  nil is returned when no timestamp exists.  As
+// the zone has a fixed offset, it does not account for daylight saving
+// time changes; prefer time.LoadLocation when the zone name is known.
+func TimeZoneFromResponse(r *Response) *time.Location {
+	if r == nil || r.Auth == nil || r.Auth.SessionTimestamp.IsZero() {
+		return nil
+	}
+	nm, offset := r.Auth.SessionTimestamp.Zone()
+	if nm == "" {
+		nm = r.Auth.SessionTimestamp.Format("UTC-07:00")
+	}
+	return time.FixedZone(nm, offset)
+}
+
+// DetectTimeZone calls getAPISession and returns the zone of the
+// response's session timestamp for use as Service.TimeZone.  See
+// TimeZoneFromResponse.
+func DetectTimeZone(ctx context.Context, sv *Service) (*time.Location, error) {
+	resp, err := sv.Exec(ctx, GetAPISession(""))
+	if err != nil {
+		return nil, err
+	}
+	if loc := TimeZoneFromResponse(resp); loc != nil {
+		return loc, nil
+	}
+	return nil, errors.New("no session timestamp returned")
+}
+
+// Midnight returns the start of the date in loc.  Use it to convert a
+// Date to an instant, e.g. for comparison with a Datetime.  A nil loc is
+// UTC.
+func (dx Date) Midnight(loc *time.Location) *time.Time {
+	if dx.IsNil() {
+		return nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	y, m, d := dx.t.Date()
+	t := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	return &t
+}
+
+// In returns the instant of the datetime in loc, nil if blank.  Values
+// decoded from intacct's MM/DD/YYYY HH:MM:SS format have no offset, so
+// their clock time is read in loc (usually Service.Location).  Other
+// values, including RFC3339 values in UTC, are converted to loc.
+func (dt Datetime) In(loc *time.Location) *time.Time {
+	if dt.IsNil() {
+		return nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	t := dt.t.In(loc)
+	if dt.clock {
+		t = time.Date(dt.t.Year(), dt.t.Month(), dt.t.Day(), dt.t.Hour(), dt.t.Minute(), dt.t.Second(), dt.t.Nanosecond(), loc)
+	}
+	return &t
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+)
+
+func TestTimeZone(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	sv := &intacct.Service{TimeZone: est}
+	if (&intacct.Service{}).Location() != time.UTC || sv.Location() != est {
+		t.Fatalf("expected default zone UTC and service zone EST")
+	}
+
+	var dt intacct.Datetime
+	if err := dt.UnmarshalText([]byte("01/02/2021 23:30:00")); err != nil {
+		t.Fatalf("unmarshal datetime: %v", err)
+	}
+	want := time.Date(2021, 1, 3, 4, 30, 0, 0, time.UTC)
+	if tm := dt.In(sv.Location()); tm == nil || !tm.Equal(want) || tm.Format(time.RFC3339) != "2021-01-02T23:30:00-05:00" {
+		t.Errorf("expected %v; got %v", want, tm)
+	}
+	if tm := dt.In(nil); tm == nil || !tm.Equal(time.Date(2021, 1, 2, 23, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected UTC clock time; got %v", tm)
+	}
+	// values with an offset keep their instant
+	if err := dt.UnmarshalText([]byte("2021-01-02T20:30:00-08:00")); err != nil {
+		t.Fatalf("unmarshal datetime: %v", err)
+	}
+	if tm := dt.In(est); tm == nil || !tm.Equal(want) {
+		t.Errorf("expected %v; got %v", want, tm)
+	}
+	// RFC3339 UTC values are instants, not clock times
+	if err := dt.UnmarshalText([]byte("2021-01-03T04:30:00Z")); err != nil {
+		t.Fatalf("unmarshal datetime: %v", err)
+	}
+	if tm := dt.In(sv.Location()); tm == nil || !tm.Equal(want) || tm.Format(time.RFC3339) != "2021-01-02T23:30:00-05:00" {
+		t.Errorf("expected %v; got %v", want, tm)
+	}
+	if tm := intacct.TimeToDatetime(want).In(est); tm == nil || !tm.Equal(want) {
+		t.Errorf("expected TimeToDatetime to keep instant %v; got %v", want, tm)
+	}
+	rm := intacct.ResultMap{"WHENMODIFIED": "01/02/2021 23:30:00"}
+	if tm, err := rm.DateTimeInE("WHENMODIFIED", sv.Location()); err != nil || !tm.Equal(want) {
+		t.Errorf("ResultMap.DateTimeInE expected %v; got %v %v", want, tm, err)
+	}
+	if tm := rm.DateTime("WHENMODIFIED"); tm == nil || tm.Hour() != 23 || tm.Location() != time.UTC {
+		t.Errorf("ResultMap.DateTime expected UTC clock time; got %v", tm)
+	}
+	if s := intacct.FormatTime(want, sv.Location()); s != "01/02/2021 23:30:00" {
+		t.Errorf("FormatTime expected 01/02/2021 23:30:00; got %s", s)
+	}
+	if tm, err := intacct.ParseTime("01/02/2021 23:30:00", est); err != nil || !tm.Equal(want) {
+		t.Errorf("ParseTime expected %v; got %v %v", want, tm, err)
+	}
+	dx := intacct.TimeToDate(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC))
+	if m := dx.Midnight(est); m == nil || !m.Equal(time.Date(2021, 1, 2, 5, 0, 0, 0, time.UTC)) {
+		t.Errorf("Midnight expected 2021-01-02 05:00 UTC; got %v", m)
+	}
+	f := intacct.NewFilter().BetweenIn("WHENDUE", want, want.Add(24*time.Hour), est)
+	if b, _ := xml.Marshal(f); !strings.Contains(string(b), "<value>01/02/2021</value><value>01/03/2021</value>") {
+		t.Errorf("BetweenIn expected dates in EST; got %s", b)
+	}
+
+	var resp *intacct.Response
+	body := `<response><control><status>success</status></control><operation><authentication><status>success</status>` +
+		`<sessiontimestamp>2021-01-06T09:06:09-08:00</sessiontimestamp></authentication></operation></response>`
+	if err := xml.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if loc := intacct.TimeZoneFromResponse(resp); loc == nil || time.Date(2021, 1, 1, 0, 0, 0, 0, loc).UTC().Hour() != 8 {
+		t.Errorf("expected -08:00 zone; got %v", loc)
+	}
+	if loc := intacct.TimeZoneFromResponse(&intacct.Response{}); loc != nil {
+		t.Errorf("expected nil zone for empty response; got %v", loc)
+	}
+
+	cfg := `{"sender_id":"S","sender_pwd":"P","login":{"user_id":"U","company":"C","password":"X"},"time_zone":"%s"}`
+	if _, err := intacct.ServiceFromConfigJSON(strings.NewReader(fmt.Sprintf(cfg, "Not/AZone"))); err == nil {
+		t.Errorf("expected error for invalid time_zone")
+	}
+	sv, err := intacct.ServiceFromConfigJSON(strings.NewReader(fmt.Sprintf(cfg, "UTC")))
+	if err != nil || sv.Location().String() != "UTC" {
+		t.Errorf("expected service zone UTC; got %v", err)
+	}
+}
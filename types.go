@@ -14,7 +14,8 @@ import (
 
 // Date used to handle intact read and readQuery date format
 type Date struct {
-	t *time.Time
+	t     *time.Time
+	clock bool // Datetime parsed without an offset (see Datetime.In)
 }
 
 // IsNil returns whether the underlying time is nil
@@ -119,7 +120,7 @@ func (dt *Datetime) UnmarshalText(text []byte) error {
 		return nil
 	}
 	if len(text) == 0 {
-		dt.t = nil
+		dt.t, dt.clock = nil, false
 		return nil
 	}
 	s := string(text)
@@ -129,14 +130,13 @@ func (dt *Datetime) UnmarshalText(text []byte) error {
 	return dt.handleRFC3339(s)
 }
 
+// handleNotRFC3339 parses intacct's MM/DD/YYYY HH:MM:SS format as UTC
+// clock time and marks the value as having no offset.  Use In to read
+// the time in the company's zone.
 func (dt *Datetime) handleNotRFC3339(s string) error {
-	parseLayout := "01/02/2006 15:04:05"
-	if len(s) == 10 {
-		parseLayout = "01/02/2006"
-	}
-	t, err := time.Parse(parseLayout, s)
+	t, err := ParseTime(s, time.UTC)
 	if err == nil {
-		dt.t = &t
+		dt.t, dt.clock = &t, true
 	}
 	return err
 }
@@ -148,7 +148,7 @@ func (dt *Datetime) handleRFC3339(s string) error {
 	}
 	t, err := time.Parse(parseLayout, s)
 	if err == nil {
-		dt.t = &t
+		dt.t, dt.clock = &t, false
 	}
 	return err
 }
@@ -206,13 +206,13 @@ func (i *Int) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
-// UnmarshalXML decodes bool values and sets value to false on any parse errors
+// UnmarshalXML decodes bool values (see ParseBool) and sets value to false
+// on any parse errors.
 func (b *Bool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var s string
 	_ = d.DecodeElement(&s, &start)
-	if val, err := strconv.ParseBool(s); err == nil {
-		*b = Bool(val)
-	}
+	val, _ := parseBoolText(s, false)
+	*b = Bool(val)
 	return nil
 }
 
@@ -164,6 +164,15 @@ func TestTypesXMLUnmarshal(t *testing.T) {
 		B2:     false,
 		Custom: []intacct.CustomField{{Name: "CustomA", Value: "A Value"}, {Name: "CustomB", Value: "X"}},
 	}
+	// Dtm1 has no offset, so its clock time is read in the zone passed to In
+	est := time.FixedZone("EST", -5*60*60)
+	if tm := xt.Dtm1.In(est); tm == nil || !tm.Equal(dtTest.Add(5*time.Hour)) || xt.Dtm1.String() != "2019-12-31T18:10:01Z" {
+		t.Errorf("expected Dtm1 clock time 18:10:01; got %v", tm)
+	}
+	if tm := xt.Dtm2.In(est); tm == nil || !tm.Equal(dtTest) {
+		t.Errorf("expected Dtm2 instant %v; got %v", dtTest, tm)
+	}
+	xt.Dtm1 = intacct.TimeToDatetime(*xt.Dtm1.Val())
 	if !reflect.DeepEqual(xt, expectedValues) {
 		t.Errorf("unmarshal intacct types wanted %#v; got %#v", expectedValues, xt)
 	}
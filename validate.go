@@ -0,0 +1,313 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryError describes a single problem found when validating a
+// Query against an object definition.
+type QueryError struct {
+	Element string // query element containing the problem (select, filter/like, orderby, etc.)
+	Field   string
+	Message string
+}
+
+// Error fulfills the error interface
+func (e QueryError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", e.Element, e.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Element, e.Field, e.Message)
+}
+
+// QueryErrors lists all problems found by ObjectType.ValidateQuery
+type QueryErrors []QueryError
+
+// Error fulfills the error interface
+func (qe QueryErrors) Error() string {
+	msgs := make([]string, 0, len(qe))
+	for _, e := range qe {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// fieldKind groups intacct data types by the operations allowed on them
+type fieldKind int
+
+const (
+	kindUnknown fieldKind = iota
+	kindText
+	kindInteger
+	kindNumber
+	kindDate
+	kindTimestamp
+	kindBool
+)
+
+// dataTypeKinds maps Lookup DATATYPE values and Inspect dataName
+// values to a fieldKind.
+var dataTypeKinds = map[string]fieldKind{
+	"TEXT":                 kindText,
+	"ENUM":                 kindText,
+	"STRING":               kindText,
+	"INTEGER":              kindInteger,
+	"SEQUENCE":             kindInteger,
+	"DECIMAL":              kindNumber,
+	"CURRENCY":             kindNumber,
+	"PERCENT":              kindNumber,
+	"NUMBER":               kindNumber,
+	"DATE":                 kindDate,
+	"TIMESTAMP":            kindTimestamp,
+	"DATETIME":             kindTimestamp,
+	"BOOLEAN":              kindBool,
+	"PT_FIELDSTRING":       kindText,
+	"PT_FIELDTEXT":         kindText,
+	"PT_FIELDINT":          kindInteger,
+	"PT_FIELDDOUBLE":       kindNumber,
+	"PT_FIELDCURRENCY":     kindNumber,
+	"PT_FIELDDATE":         kindDate,
+	"PT_FIELDDATETIME":     kindTimestamp,
+	"PT_FIELDBOOLEAN":      kindBool,
+	"PT_FIELDRELATIONSHIP": kindText,
+}
+
+func kindOf(dataType string) fieldKind {
+	return dataTypeKinds[strings.ToUpper(dataType)]
+}
+
+func (k fieldKind) isNumeric() bool {
+	return k == kindInteger || k == kindNumber
+}
+
+func (k fieldKind) isTime() bool {
+	return k == kindDate || k == kindTimestamp
+}
+
+// Field returns the definition of the named field.  Names are
+// not case sensitive.
+func (ot *ObjectType) Field(id string) (ObjectField, bool) {
+	if ot != nil {
+		for _, f := range ot.Fields {
+			if strings.EqualFold(f.ID, id) {
+				return f, true
+			}
+		}
+	}
+	return ObjectField{}, false
+}
+
+// Relationship returns the relationship whose path or related object
+// name matches nm.
+func (ot *ObjectType) Relationship(nm string) (ObjectRelationship, bool) {
+	if ot != nil {
+		for _, r := range ot.Relationships {
+			if strings.EqualFold(r.Path, nm) || strings.EqualFold(r.Name, nm) {
+				return r, true
+			}
+		}
+	}
+	return ObjectRelationship{}, false
+}
+
+// resolve returns the field definition for id. isRelated is true when id
+// references a field of a related object which cannot be checked without
+// that object's definition.
+func (ot *ObjectType) resolve(id string) (fld ObjectField, isRelated bool, ok bool) {
+	if fld, ok = ot.Field(id); ok {
+		return fld, false, true
+	}
+	if idx := strings.LastIndex(id, "."); idx > 0 {
+		if _, ok = ot.Relationship(id[:idx]); ok {
+			return fld, true, true
+		}
+	}
+	return fld, false, false
+}
+
+// ValidateQuery checks q against the object definition returned by a Lookup
+// function.  Selected, filtered and sorted fields must exist, filter operators
+// must suit the field's data type, values must be in the field's ValidValues
+// when defined, and aggregate fields must be numeric.  Fields of related
+// objects (RELATIONSHIP.FIELD) are accepted when the relationship exists.
+// A nil result indicates no problems were found.  As ValidateQuery makes no
+// calls to intacct, ot may be a cached definition.
+func (ot *ObjectType) ValidateQuery(q Query) QueryErrors {
+	if ot == nil {
+		return QueryErrors{{Element: "object", Field: q.Object, Message: "no object definition"}}
+	}
+	v := &queryValidator{ot: ot}
+	if !strings.EqualFold(q.Object, ot.Name) {
+		v.add("object", q.Object, fmt.Sprintf("does not match definition %s", ot.Name))
+	}
+	v.checkSelect(q.Select)
+	if q.Filter != nil {
+		v.checkFilter(q.Filter, "filter")
+	}
+	if q.Sort != nil {
+		for _, o := range q.Sort.Fields {
+			v.checkField("orderby", o.Field)
+		}
+	}
+	return v.errs
+}
+
+type queryValidator struct {
+	ot   *ObjectType
+	errs QueryErrors
+}
+
+func (v *queryValidator) add(element, field, msg string) {
+	v.errs = append(v.errs, QueryError{Element: element, Field: field, Message: msg})
+}
+
+// checkField reports unknown fields and returns the field definition along with
+// whether the definition is available for further checks.
+func (v *queryValidator) checkField(element, id string) (ObjectField, bool) {
+	if id == "" {
+		v.add(element, id, "empty field name")
+		return ObjectField{}, false
+	}
+	fld, isRelated, ok := v.ot.resolve(id)
+	if !ok {
+		v.add(element, id, "unknown field")
+		return fld, false
+	}
+	return fld, !isRelated
+}
+
+func (v *queryValidator) checkSelect(sel Select) {
+	s := sel.xmlForm()
+	if len(s.Fields) == 0 && !sel.HasAggregates() {
+		v.add("select", "", "no fields selected")
+	}
+	for _, id := range s.Fields {
+		v.checkField("select", id)
+	}
+	for _, id := range s.Count {
+		v.checkField("count", id)
+	}
+	for _, agg := range []struct {
+		element string
+		ids     []string
+		allowTm bool
+	}{
+		{"avg", s.Avg, false},
+		{"sum", s.Sum, false},
+		{"min", s.Min, true},
+		{"max", s.Max, true},
+	} {
+		for _, id := range agg.ids {
+			fld, ok := v.checkField(agg.element, id)
+			if !ok {
+				continue
+			}
+			if k := kindOf(fld.DataType); k != kindUnknown && !k.isNumeric() && !(agg.allowTm && k.isTime()) {
+				v.add(agg.element, id, fmt.Sprintf("aggregate not allowed on %s field", fld.DataType))
+			}
+		}
+	}
+}
+
+func (v *queryValidator) checkFilter(f *Filter, element string) {
+	op := f.XMLName.Local
+	switch op {
+	case "", "filter", "and", "or":
+		if f.Field != "" {
+			v.add(element, f.Field, "field not allowed on grouping filter")
+		}
+		for i := range f.Filters {
+			v.checkFilter(&f.Filters[i], "filter/"+f.Filters[i].XMLName.Local)
+		}
+		return
+	}
+	fld, ok := v.checkField(element, f.Field)
+	if !ok {
+		return
+	}
+	k := kindOf(fld.DataType)
+	switch op {
+	case "like", "notlike":
+		if k != kindUnknown && k != kindText {
+			v.add(element, f.Field, fmt.Sprintf("%s not allowed on %s field", op, fld.DataType))
+		}
+		return
+	case "lessthan", "lessthanorequalto", "greaterthan", "greaterthanorequalto":
+		if k == kindBool {
+			v.add(element, f.Field, fmt.Sprintf("%s not allowed on %s field", op, fld.DataType))
+			return
+		}
+	case "between":
+		if k != kindUnknown && !k.isNumeric() && !k.isTime() {
+			v.add(element, f.Field, fmt.Sprintf("%s not allowed on %s field", op, fld.DataType))
+			return
+		}
+	case "isnull", "isnotnull":
+		return
+	case "equalto", "notequalto", "in", "notin":
+		v.checkValidValues(element, fld, f.Value)
+	default:
+		v.add(element, f.Field, fmt.Sprintf("unknown operator %s", op))
+		return
+	}
+	for _, val := range f.Value {
+		if err := checkValue(k, val); err != nil {
+			v.add(element, f.Field, fmt.Sprintf("invalid value %q: %v", val, err))
+		}
+	}
+}
+
+func (v *queryValidator) checkValidValues(element string, fld ObjectField, vals []string) {
+	if len(fld.ValidValues) == 0 {
+		return
+	}
+	for _, val := range vals {
+		var found bool
+		for _, vv := range fld.ValidValues {
+			if val == vv {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(element, fld.ID, fmt.Sprintf("%q not in valid values %s", val, strings.Join(fld.ValidValues, ", ")))
+		}
+	}
+}
+
+// checkValue verifies that a filter value may be parsed as
+// the field's kind.
+func checkValue(k fieldKind, val string) error {
+	var err error
+	switch k {
+	case kindInteger:
+		_, err = strconv.ParseInt(val, 10, 64)
+	case kindNumber:
+		_, err = strconv.ParseFloat(val, 64)
+	case kindBool:
+		_, err = ParseBool(val)
+	case kindDate:
+		if _, err = time.Parse("01/02/2006", val); err != nil {
+			err = errors.New("expected MM/DD/YYYY")
+		}
+	case kindTimestamp:
+		if _, err = time.Parse("01/02/2006 15:04:05", val); err != nil {
+			if _, err = time.Parse("01/02/2006", val); err != nil {
+				err = errors.New("expected MM/DD/YYYY HH:MM:SS")
+			}
+		}
+	}
+	if numErr, ok := err.(*strconv.NumError); ok {
+		return numErr.Err
+	}
+	return err
+}
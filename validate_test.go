@@ -0,0 +1,123 @@
+// Copyright 2020 James Cote
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intacct_test
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/jfcote87/intacct"
+)
+
+const vendorLookup = `<Type Name="VENDOR" DocumentType="">
+	<Fields>
+		<Field><ID>RECORDNO</ID><LABEL>Record number</LABEL><REQUIRED>false</REQUIRED><READONLY>true</READONLY><DATATYPE>INTEGER</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>VENDORID</ID><LABEL>Vendor ID</LABEL><REQUIRED>true</REQUIRED><READONLY>false</READONLY><DATATYPE>TEXT</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>NAME</ID><LABEL>Vendor name</LABEL><REQUIRED>true</REQUIRED><READONLY>false</READONLY><DATATYPE>TEXT</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>STATUS</ID><LABEL>Status</LABEL><REQUIRED>false</REQUIRED><READONLY>false</READONLY><DATATYPE>TEXT</DATATYPE><ISCUSTOM>false</ISCUSTOM>
+			<VALIDVALUES><VALIDVALUE>active</VALIDVALUE><VALIDVALUE>inactive</VALIDVALUE></VALIDVALUES></Field>
+		<Field><ID>TOTALDUE</ID><LABEL>Total due</LABEL><REQUIRED>false</REQUIRED><READONLY>true</READONLY><DATATYPE>CURRENCY</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>ONHOLD</ID><LABEL>On hold</LABEL><REQUIRED>false</REQUIRED><READONLY>false</READONLY><DATATYPE>BOOLEAN</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>WHENMODIFIED</ID><LABEL>When modified</LABEL><REQUIRED>false</REQUIRED><READONLY>true</READONLY><DATATYPE>TIMESTAMP</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>LASTPAID</ID><LABEL>Last paid</LABEL><REQUIRED>false</REQUIRED><READONLY>true</READONLY><DATATYPE>DATE</DATATYPE><ISCUSTOM>false</ISCUSTOM></Field>
+		<Field><ID>REGION</ID><LABEL>Region</LABEL><REQUIRED>false</REQUIRED><READONLY>false</READONLY><DATATYPE>TEXT</DATATYPE><ISCUSTOM>true</ISCUSTOM></Field>
+	</Fields>
+	<Relationships>
+		<Relationship><OBJECTPATH>TERM</OBJECTPATH><OBJECTNAME>APTERM</OBJECTNAME><LABEL>Term</LABEL><RELATIONSHIPTYPE>MANY2ONE</RELATIONSHIPTYPE><RELATEDBY>TERMNAME</RELATEDBY></Relationship>
+	</Relationships>
+</Type>`
+
+func getVendorLookup(t *testing.T) *intacct.ObjectType {
+	var ot *intacct.ObjectType
+	if err := xml.Unmarshal([]byte(vendorLookup), &ot); err != nil {
+		t.Fatalf("unable to unmarshal lookup: %v", err)
+	}
+	return ot
+}
+
+func TestObjectType_ValidateQuery(t *testing.T) {
+	ot := getVendorLookup(t)
+	var f *intacct.Filter
+	tests := []struct {
+		name string
+		q    intacct.Query
+		want []intacct.QueryError
+	}{
+		{
+			name: "valid",
+			q: intacct.Query{
+				Object: "VENDOR",
+				Select: intacct.Select{Fields: []string{"RECORDNO", "name", "TERM.NAME", "REGION"}, Sum: "TOTALDUE"},
+				Filter: intacct.NewFilter().And().EqualTo("STATUS", "active").Like("NAME", "A%").
+					GreaterThanOrEqualTo("WHENMODIFIED", "01/02/2020 13:04:05").Between("LASTPAID", time.Now().AddDate(0, -1, 0), time.Now()).Parent(),
+				Sort: &intacct.QuerySort{Fields: []intacct.OrderBy{{Field: "VENDORID"}}},
+			},
+		},
+		{
+			name: "unknown fields",
+			q: intacct.Query{
+				Object: "VENDOR",
+				Select: intacct.Select{Fields: []string{"RECORDNO", "NAMEX", "BADREL.NAME"}},
+				Sort:   &intacct.QuerySort{Fields: []intacct.OrderBy{{Field: "VENDORIDX"}}},
+			},
+			want: []intacct.QueryError{
+				{Element: "select", Field: "NAMEX", Message: "unknown field"},
+				{Element: "select", Field: "BADREL.NAME", Message: "unknown field"},
+				{Element: "orderby", Field: "VENDORIDX", Message: "unknown field"},
+			},
+		},
+		{
+			name: "operators and values",
+			q: intacct.Query{
+				Object: "VENDOR",
+				Select: intacct.Select{Fields: []string{"RECORDNO"}},
+				Filter: f.Like("WHENMODIFIED", "2020%").EqualTo("STATUS", "Active").
+					LessThan("ONHOLD", "true").EqualTo("RECORDNO", "1.5").In("LASTPAID", "2020-01-01"),
+			},
+			want: []intacct.QueryError{
+				{Element: "filter/like", Field: "WHENMODIFIED", Message: "like not allowed on TIMESTAMP field"},
+				{Element: "filter/equalto", Field: "STATUS", Message: `"Active" not in valid values active, inactive`},
+				{Element: "filter/lessthan", Field: "ONHOLD", Message: "lessthan not allowed on BOOLEAN field"},
+				{Element: "filter/equalto", Field: "RECORDNO", Message: `invalid value "1.5": invalid syntax`},
+				{Element: "filter/in", Field: "LASTPAID", Message: `invalid value "2020-01-01": expected MM/DD/YYYY`},
+			},
+		},
+		{
+			name: "aggregates",
+			q: intacct.Query{
+				Object: "vendor",
+				Select: intacct.Select{Sum: "TOTALDUE", SumFields: []string{"NAME"}, Min: "LASTPAID", Avg: "WHENMODIFIED", Count: "NOFIELD"},
+			},
+			want: []intacct.QueryError{
+				{Element: "count", Field: "NOFIELD", Message: "unknown field"},
+				{Element: "avg", Field: "WHENMODIFIED", Message: "aggregate not allowed on TIMESTAMP field"},
+				{Element: "sum", Field: "NAME", Message: "aggregate not allowed on TEXT field"},
+			},
+		},
+		{
+			name: "wrong object",
+			q:    intacct.Query{Object: "CUSTOMER"},
+			want: []intacct.QueryError{
+				{Element: "object", Field: "CUSTOMER", Message: "does not match definition VENDOR"},
+				{Element: "select", Message: "no fields selected"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ot.ValidateQuery(tt.q)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d errors; got %d %v", len(tt.want), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected error %d %#v; got %#v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}